@@ -0,0 +1,260 @@
+package main
+
+import (
+	"fmt"
+	"hf-utils/mempool"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Config struct {
+	Scenario string
+	Pool     string
+	ObjSize  int
+	CPUs     int
+	Parallel int
+	Shards   int
+	Capacity int64
+	Batch    int64
+	Duration time.Duration
+}
+
+type Result struct {
+	Scenario      string  `json:"scenario"`
+	Pool          string  `json:"pool"`
+	ObjSize       int     `json:"obj_size"`
+	CPUs          int     `json:"cpus"`
+	Parallel      int     `json:"parallel"`
+	Shards        int     `json:"shards"`
+	Ops           int64   `json:"ops"`
+	OpsPerSec     float64 `json:"ops_per_sec"`
+	AllocFailRate float64 `json:"alloc_fail_rate"`
+	FreeFailRate  float64 `json:"free_fail_rate"`
+	GCPause       string  `json:"gc_pause"`
+}
+
+type counts struct {
+	ops, alloc, allocFailed, free, freeFailed int64
+}
+
+func (c *counts) add(o counts) {
+	c.ops += o.ops
+	c.alloc += o.alloc
+	c.allocFailed += o.allocFailed
+	c.free += o.free
+	c.freeFailed += o.freeFailed
+}
+
+func rate(n, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+// measure runs worker on cpus*parallel goroutines until cfg.Duration has
+// passed and aggregates what they report.
+func measure(cfg Config, worker func(id int, stop *atomic.Bool) counts) Result {
+	runtime.GOMAXPROCS(cfg.CPUs)
+	workers := cfg.CPUs * cfg.Parallel
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var total counts
+	start := time.Now()
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			c := worker(id, &stop)
+			mu.Lock()
+			total.add(c)
+			mu.Unlock()
+		}(i)
+	}
+	time.Sleep(cfg.Duration)
+	stop.Store(true)
+	wg.Wait()
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	return Result{
+		Scenario:      cfg.Scenario,
+		Pool:          cfg.Pool,
+		ObjSize:       cfg.ObjSize,
+		CPUs:          cfg.CPUs,
+		Parallel:      cfg.Parallel,
+		Shards:        cfg.Shards,
+		Ops:           total.ops,
+		OpsPerSec:     float64(total.ops) / elapsed.Seconds(),
+		AllocFailRate: rate(total.allocFailed, total.alloc),
+		FreeFailRate:  rate(total.freeFailed, total.free),
+		GCPause:       time.Duration(after.PauseTotalNs - before.PauseTotalNs).String(),
+	}
+}
+
+type benchPool[O any] interface {
+	Init(int64)
+	New() *O
+	Free(*O) bool
+}
+
+type rawPool[O any] struct{}
+
+func (rawPool[O]) Init(int64)   {}
+func (rawPool[O]) New() *O      { return new(O) }
+func (rawPool[O]) Free(*O) bool { return true }
+
+func newPool[O any](name string) (func() benchPool[O], error) {
+	switch name {
+	case "casq":
+		return func() benchPool[O] { return new(mempool.MemPool[O]) }, nil
+	case "chan":
+		return func() benchPool[O] { return new(mempool.ChMemPool[O]) }, nil
+	case "stack":
+		return func() benchPool[O] { return new(mempool.StackMemPool[O]) }, nil
+	case "mpmc":
+		return func() benchPool[O] { return new(mempool.MPMCMemPool[O]) }, nil
+	case "raw":
+		return func() benchPool[O] { return rawPool[O]{} }, nil
+	}
+	return nil, fmt.Errorf("unknown pool type %q", name)
+}
+
+// runPool is the PoolTester/ShardTester scenario: every worker keeps Batch
+// slots and alternately allocates and frees each one, spreading requests
+// round-robin over Shards pools.
+func runPool[O any](cfg Config, touch func(*O)) (Result, error) {
+	maker, err := newPool[O](cfg.Pool)
+	if err != nil {
+		return Result{}, err
+	}
+	pools := make([]benchPool[O], cfg.Shards)
+	for i := range pools {
+		pools[i] = maker()
+		pools[i].Init(cfg.Capacity / int64(cfg.Shards))
+	}
+	return measure(cfg, func(id int, stop *atomic.Bool) counts {
+		var c counts
+		array := make([]*O, cfg.Batch)
+		owner := make([]int, cfg.Batch)
+		for i := int64(0); ; i++ {
+			if i&0xFF == 0 && stop.Load() {
+				break
+			}
+			c.ops++
+			slot := i % cfg.Batch
+			if array[slot] == nil {
+				c.alloc++
+				shard := int(i % int64(cfg.Shards))
+				ptr := pools[shard].New()
+				if ptr == nil {
+					c.allocFailed++
+					runtime.Gosched()
+					continue
+				}
+				touch(ptr)
+				array[slot], owner[slot] = ptr, shard
+			} else {
+				c.free++
+				if pools[owner[slot]].Free(array[slot]) {
+					array[slot] = nil
+				} else {
+					c.freeFailed++
+					runtime.Gosched()
+				}
+			}
+		}
+		for slot, ptr := range array {
+			if ptr != nil {
+				pools[owner[slot]].Free(ptr)
+			}
+		}
+		return c
+	}), nil
+}
+
+// runQueue is the QueueTester scenario run on a pool's free list on its own:
+// the queue starts full and every worker holds Batch values, pushing back
+// what it holds and popping otherwise.
+func runQueue(cfg Config) (Result, error) {
+	var q mempool.IQueue
+	switch cfg.Pool {
+	case "casq":
+		q = new(mempool.CASQueue)
+	case "chan":
+		q = new(mempool.ChanQueue)
+	case "stack":
+		q = new(mempool.TreiberStack)
+	case "mpmc":
+		q = new(mempool.Queue[int64])
+	default:
+		return Result{}, fmt.Errorf("unknown queue type %q", cfg.Pool)
+	}
+	q.Init(cfg.Capacity)
+	for i := int64(0); i < cfg.Capacity; i++ {
+		q.Push(i)
+	}
+	return measure(cfg, func(id int, stop *atomic.Bool) counts {
+		var c counts
+		data := make([]int64, cfg.Batch)
+		for i := range data {
+			data[i] = -1
+		}
+		for i := int64(0); ; i++ {
+			if i&0xFF == 0 && stop.Load() {
+				break
+			}
+			c.ops++
+			slot := i % cfg.Batch
+			if data[slot] >= 0 {
+				c.free++
+				if q.Push(data[slot]) {
+					data[slot] = -1
+				} else {
+					c.freeFailed++
+				}
+			} else {
+				c.alloc++
+				if v, ok := q.Pop(); ok {
+					data[slot] = v
+				} else {
+					c.allocFailed++
+				}
+			}
+		}
+		return c
+	}), nil
+}
+
+type object[D any] struct {
+	Data D
+}
+
+func run(cfg Config) (Result, error) {
+	if cfg.Scenario == "queue" {
+		return runQueue(cfg)
+	}
+	if cfg.Scenario != "pool" {
+		return Result{}, fmt.Errorf("unknown scenario %q", cfg.Scenario)
+	}
+	switch cfg.ObjSize {
+	case 16:
+		return runPool(cfg, func(o *object[[1 << 4]byte]) { o.Data[0]++ })
+	case 64:
+		return runPool(cfg, func(o *object[[1 << 6]byte]) { o.Data[0]++ })
+	case 256:
+		return runPool(cfg, func(o *object[[1 << 8]byte]) { o.Data[0]++ })
+	case 1024:
+		return runPool(cfg, func(o *object[[1 << 10]byte]) { o.Data[0]++ })
+	case 4096:
+		return runPool(cfg, func(o *object[[1 << 12]byte]) { o.Data[0]++ })
+	}
+	return Result{}, fmt.Errorf("unsupported object size %d, use 16/64/256/1024/4096", cfg.ObjSize)
+}
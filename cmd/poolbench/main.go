@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func parseInts(s string) ([]int, error) {
+	var result []int
+	for _, f := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", f)
+		}
+		if v <= 0 {
+			return nil, fmt.Errorf("%d must be positive", v)
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// checkSizes makes sure every shard gets at least one slot and every worker
+// holds at least one.
+func checkSizes(capacity, batch int64, shards []int) error {
+	for _, s := range shards {
+		if int64(s) > capacity {
+			return fmt.Errorf("capacity %d is smaller than %d shards", capacity, s)
+		}
+	}
+	if batch <= 0 {
+		return fmt.Errorf("batch %d must be positive", batch)
+	}
+	return nil
+}
+
+func parseNames(s string) []string {
+	var result []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+var header = []string{
+	"scenario", "pool", "obj_size", "cpus", "parallel", "shards",
+	"ops", "ops/s", "alloc_fail", "free_fail", "gc_pause",
+}
+
+func (r Result) row() []string {
+	return []string{
+		r.Scenario, r.Pool,
+		strconv.Itoa(r.ObjSize), strconv.Itoa(r.CPUs), strconv.Itoa(r.Parallel), strconv.Itoa(r.Shards),
+		strconv.FormatInt(r.Ops, 10),
+		strconv.FormatFloat(r.OpsPerSec, 'f', 0, 64),
+		strconv.FormatFloat(r.AllocFailRate, 'f', 4, 64),
+		strconv.FormatFloat(r.FreeFailRate, 'f', 4, 64),
+		r.GCPause,
+	}
+}
+
+func report(w io.Writer, format string, results []Result) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write(header)
+		for _, r := range results {
+			cw.Write(r.row())
+		}
+		cw.Flush()
+		return cw.Error()
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(tw, strings.Join(header, "\t")+"\t")
+		for _, r := range results {
+			fmt.Fprintln(tw, strings.Join(r.row(), "\t")+"\t")
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+func main() {
+	scenarios := flag.String("scenario", "pool", "comma separated scenarios: pool, queue")
+	pools := flag.String("pools", "casq,chan,stack,mpmc,raw", "pool types: casq, chan, stack, mpmc, raw (the queue scenario runs each pool's free list and skips raw)")
+	sizes := flag.String("sizes", "16,256,4096", "object sizes in bytes")
+	cpus := flag.String("cpus", "1,2,4", "GOMAXPROCS values")
+	parallel := flag.String("parallel", "1", "goroutines per CPU")
+	shards := flag.String("shards", "1", "number of pool shards")
+	capacity := flag.Int64("capacity", 1<<16, "total slots per pool or queue")
+	batch := flag.Int64("batch", 1<<12, "slots held per goroutine")
+	duration := flag.Duration("duration", time.Second, "run time per case")
+	format := flag.String("format", "table", "output format: table, json, csv")
+	flag.Parse()
+
+	var err error
+	var sizeList, cpuList, parList, shardList []int
+	for _, p := range []struct {
+		dst *[]int
+		s   string
+	}{{&sizeList, *sizes}, {&cpuList, *cpus}, {&parList, *parallel}, {&shardList, *shards}} {
+		if *p.dst, err = parseInts(p.s); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+	if err := checkSizes(*capacity, *batch, shardList); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	var results []Result
+	for _, scenario := range parseNames(*scenarios) {
+		for _, pool := range parseNames(*pools) {
+			for _, size := range sizeList {
+				for _, c := range cpuList {
+					for _, p := range parList {
+						for _, s := range shardList {
+							cfg := Config{
+								Scenario: scenario,
+								Pool:     pool,
+								ObjSize:  size,
+								CPUs:     c,
+								Parallel: p,
+								Shards:   s,
+								Capacity: *capacity,
+								Batch:    *batch,
+								Duration: *duration,
+							}
+							if scenario == "queue" {
+								// object size and shards do not apply to queues,
+								// and raw has no free list
+								if size != sizeList[0] || s != shardList[0] || pool == "raw" {
+									continue
+								}
+								cfg.ObjSize, cfg.Shards = 8, 1
+							}
+							r, err := run(cfg)
+							if err != nil {
+								fmt.Fprintln(os.Stderr, err)
+								os.Exit(1)
+							}
+							results = append(results, r)
+						}
+					}
+				}
+			}
+		}
+	}
+	if err := report(os.Stdout, *format, results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -14,6 +14,11 @@ type casQueue struct {
 	size int64
 	w    int64 // write position
 	r    int64 // read position
+
+	pushFailed  atomic.Int64
+	popFailed   atomic.Int64
+	pushRetries atomic.Int64
+	popRetries  atomic.Int64
 }
 
 func (q *casQueue) Init(size int64) {
@@ -35,6 +40,7 @@ func (q *casQueue) Push(idx int64) bool {
 		if q.notFull(w, r) {
 			data := &q.data[w%q.size]
 			if data.t.Load() {
+				q.pushFailed.Add(1)
 				return false
 			}
 			if atomic.CompareAndSwapInt64(&q.w, w, w+1) {
@@ -42,7 +48,9 @@ func (q *casQueue) Push(idx int64) bool {
 				data.t.Store(true)
 				return true
 			}
+			q.pushRetries.Add(1)
 		} else {
+			q.pushFailed.Add(1)
 			return false
 		}
 	}
@@ -64,6 +72,7 @@ func (q *casQueue) Pop() (int64, bool) {
 		if q.notEmpty(w, r) {
 			d := &q.data[r%q.size]
 			if !d.t.Load() {
+				q.popFailed.Add(1)
 				return -1, false
 			}
 			data := d.d
@@ -71,7 +80,9 @@ func (q *casQueue) Pop() (int64, bool) {
 				d.t.Store(false)
 				return data, true
 			}
+			q.popRetries.Add(1)
 		} else {
+			q.popFailed.Add(1)
 			return -1, false
 		}
 	}
@@ -80,3 +91,74 @@ func (q *casQueue) Pop() (int64, bool) {
 func (q *casQueue) notEmpty(w, r int64) bool {
 	return r < w
 }
+
+// PushN claims up to len(idxs) slots with one CAS and returns how many
+// were pushed.
+func (q *casQueue) PushN(idxs []int64) int {
+	for {
+		w := atomic.LoadInt64(&q.w)
+		r := atomic.LoadInt64(&q.r)
+		n := q.size - (w - r)
+		if n > int64(len(idxs)) {
+			n = int64(len(idxs))
+		}
+		free := int64(0)
+		for free < n && !q.data[(w+free)%q.size].t.Load() {
+			free++
+		}
+		if free == 0 {
+			q.pushFailed.Add(1)
+			return 0
+		}
+		if atomic.CompareAndSwapInt64(&q.w, w, w+free) {
+			for i := int64(0); i < free; i++ {
+				data := &q.data[(w+i)%q.size]
+				data.d = idxs[i]
+				data.t.Store(true)
+			}
+			return int(free)
+		}
+		q.pushRetries.Add(1)
+	}
+}
+
+// PopN claims up to len(dst) slots with one CAS and returns how many were
+// popped.
+func (q *casQueue) PopN(dst []int64) int {
+	for {
+		w := atomic.LoadInt64(&q.w)
+		r := atomic.LoadInt64(&q.r)
+		n := w - r
+		if n > int64(len(dst)) {
+			n = int64(len(dst))
+		}
+		ready := int64(0)
+		for ready < n && q.data[(r+ready)%q.size].t.Load() {
+			ready++
+		}
+		if ready == 0 {
+			q.popFailed.Add(1)
+			return 0
+		}
+		if atomic.CompareAndSwapInt64(&q.r, r, r+ready) {
+			for i := int64(0); i < ready; i++ {
+				d := &q.data[(r+i)%q.size]
+				dst[i] = d.d
+				d.t.Store(false)
+			}
+			return int(ready)
+		}
+		q.popRetries.Add(1)
+	}
+}
+
+func (q *casQueue) Stats() QueueStats {
+	return QueueStats{
+		Capacity:    q.size,
+		Len:         atomic.LoadInt64(&q.w) - atomic.LoadInt64(&q.r),
+		PushFailed:  q.pushFailed.Load(),
+		PopFailed:   q.popFailed.Load(),
+		PushRetries: q.pushRetries.Load(),
+		PopRetries:  q.popRetries.Load(),
+	}
+}
@@ -0,0 +1,166 @@
+package mempool
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+var pageSize = os.Getpagesize()
+
+// HasPointers reports whether values of t hold anything the GC would have to
+// scan, which rules them out of off-heap or serialized storage.
+func HasPointers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Array:
+		return t.Len() > 0 && HasPointers(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if HasPointers(t.Field(i).Type) {
+				return true
+			}
+		}
+		return false
+	case reflect.Ptr, reflect.UnsafePointer, reflect.Slice, reflect.String,
+		reflect.Map, reflect.Chan, reflect.Func, reflect.Interface:
+		return true
+	default:
+		return false
+	}
+}
+
+// InitArena is Init with the slab placed in anonymous mmap'd memory instead
+// of the Go heap, so it does not count toward GOGC growth. T must not contain
+// pointers, since the GC does not scan the arena. Call Release when done.
+func (m *Pool[T, Q, PQ]) InitArena(size int64) error {
+	var t T
+	if typ := reflect.TypeOf(t); HasPointers(typ) {
+		return fmt.Errorf("arena pool: type %s contains pointers", typ)
+	}
+	slab, err := mapArena[T](size)
+	if err != nil {
+		return err
+	}
+	m.cache.initSlab(slab)
+	m.cache.arena = true
+	m.cache.initSlabs()
+	m.initQueue(size)
+	return nil
+}
+
+// Release unmaps an arena slab. Every pointer handed out by the pool becomes
+// invalid, and the pool must be initialized again before reuse. A TrimEvery
+// still running afterwards finds nothing to trim.
+func (m *Pool[T, Q, PQ]) Release() error {
+	m.arenaMu.Lock()
+	defer m.arenaMu.Unlock()
+	if !m.cache.arena {
+		return nil
+	}
+	slab := m.cache.cache
+	m.cache = bitmapCache[T]{zero: m.cache.zero, elemSize: m.cache.elemSize}
+	PQ(&m.queue).Init(0)
+	return unmapArena(slab)
+}
+
+const arenaSlabBytes = 1 << 16
+
+// arenaSlab tracks how many slots of one slab of the arena are in use.
+// Trim holds live at -1 while it returns the slab's pages to the OS.
+type arenaSlab struct {
+	live     atomic.Int64
+	idleFrom atomic.Int64
+	trimmed  atomic.Bool
+}
+
+func (c *bitmapCache[T]) initSlabs() {
+	c.slabSlots = int64(arenaSlabBytes / c.elemSize)
+	if c.slabSlots == 0 {
+		c.slabSlots = 1
+	}
+	c.slabs = make([]arenaSlab, (c.size+c.slabSlots-1)/c.slabSlots)
+	now := time.Now().UnixNano()
+	for i := range c.slabs {
+		c.slabs[i].idleFrom.Store(now)
+	}
+}
+
+func (c *bitmapCache[T]) claimSlab(idx int64) {
+	slab := &c.slabs[idx/c.slabSlots]
+	for {
+		n := slab.live.Load()
+		if n >= 0 && slab.live.CompareAndSwap(n, n+1) {
+			if n == 0 {
+				slab.trimmed.Store(false)
+			}
+			return
+		}
+		runtime.Gosched()
+	}
+}
+
+func (c *bitmapCache[T]) releaseSlab(idx int64) {
+	slab := &c.slabs[idx/c.slabSlots]
+	if slab.live.Add(-1) == 0 {
+		slab.idleFrom.Store(time.Now().UnixNano())
+	}
+}
+
+// Trim returns the pages of every arena slab that has had no live objects for
+// at least idle back to the OS. The address range stays mapped and is
+// faulted back in, zeroed, on the next allocation. It returns the number of
+// bytes released; heap-backed pools release nothing.
+func (m *Pool[T, Q, PQ]) Trim(idle time.Duration) int64 {
+	m.arenaMu.Lock()
+	defer m.arenaMu.Unlock()
+	c := &m.cache
+	if c.slabs == nil {
+		return 0
+	}
+	deadline := time.Now().Add(-idle).UnixNano()
+	page := uintptr(pageSize)
+	mem := unsafe.Slice((*byte)(unsafe.Pointer(&c.cache[0])), uintptr(c.size)*c.elemSize)
+	var released int64
+	for i := range c.slabs {
+		slab := &c.slabs[i]
+		if slab.trimmed.Load() || slab.idleFrom.Load() > deadline {
+			continue
+		}
+		if !slab.live.CompareAndSwap(0, -1) {
+			continue
+		}
+		first := int64(i) * c.slabSlots
+		last := first + c.slabSlots
+		if last > c.size {
+			last = c.size
+		}
+		// the arena is page aligned, so offsets can be rounded directly
+		start := (uintptr(first)*c.elemSize + page - 1) / page * page
+		end := uintptr(last) * c.elemSize / page * page
+		if end > start && adviseFree(mem[start:end]) == nil {
+			released += int64(end - start)
+		}
+		slab.trimmed.Store(true)
+		slab.live.Store(0)
+	}
+	return released
+}
+
+// TrimEvery calls Trim(idle) every interval until ctx is done.
+func (m *Pool[T, Q, PQ]) TrimEvery(ctx context.Context, interval, idle time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Trim(idle)
+		}
+	}
+}
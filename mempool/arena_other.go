@@ -0,0 +1,17 @@
+//go:build !unix
+
+package mempool
+
+import "fmt"
+
+func mapArena[T any](size int64) ([]T, error) {
+	return nil, fmt.Errorf("arena pool is not supported on this platform")
+}
+
+func unmapArena[T any](slab []T) error {
+	return nil
+}
+
+func adviseFree(mem []byte) error {
+	return nil
+}
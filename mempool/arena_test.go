@@ -0,0 +1,141 @@
+//go:build unix
+
+package mempool
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+type depthLevel struct {
+	Price  int64
+	Volume int64
+}
+
+type depthRecord struct {
+	Timestamp int64
+	Asks      [100]depthLevel
+	Bids      [100]depthLevel
+}
+
+func TestArenaPool(t *testing.T) {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	pool := &MemPool[depthRecord]{}
+	if err := pool.InitArena(1 << 12); err != nil {
+		t.Fatal(err)
+	}
+	runtime.ReadMemStats(&after)
+	// 4096 records of ~3.2KB would be ~13MB on the heap
+	if grown := int64(after.HeapAlloc) - int64(before.HeapAlloc); grown > 1<<20 {
+		t.Fatalf("arena slab counted on heap: grew %d bytes", grown)
+	}
+
+	ptr := pool.New()
+	ptr.Asks[99].Volume = 42
+	if !pool.Free(ptr) {
+		t.Fatal("free arena slot failed")
+	}
+	if err := pool.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if pool.New() != nil || pool.Free(ptr) {
+		t.Fatal("released pool should not allocate or free")
+	}
+}
+
+func TestArenaRejectsPointers(t *testing.T) {
+	if err := new(MemPool[resetRecord]).InitArena(16); err == nil {
+		t.Fatal("type with slice should be rejected")
+	}
+	if err := new(StackMemPool[object256]).InitArena(16); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestArenaTrim(t *testing.T) {
+	pool := &MemPool[depthRecord]{}
+	if err := pool.InitArena(1 << 10); err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Release()
+
+	ptrs := make([]*depthRecord, 1<<10)
+	for i := range ptrs {
+		ptrs[i] = pool.New()
+		ptrs[i].Timestamp = int64(i) + 1
+	}
+	kept := ptrs[0]
+	for _, ptr := range ptrs[1:] {
+		pool.Free(ptr)
+	}
+	if n := pool.Trim(time.Hour); n != 0 {
+		t.Fatalf("trimmed %d bytes of recently used slabs", n)
+	}
+	released := pool.Trim(0)
+	if released == 0 {
+		t.Fatal("nothing trimmed")
+	}
+	if n := pool.Trim(0); n != 0 {
+		t.Fatalf("trimmed %d bytes twice", n)
+	}
+	if kept.Timestamp != 1 {
+		t.Fatal("live slab was trimmed")
+	}
+	if last := &pool.cache.cache[len(ptrs)-1]; last.Timestamp != 0 {
+		t.Fatalf("trimmed slot still holds %d", last.Timestamp)
+	}
+	t.Logf("released %d bytes", released)
+
+	for i := 1; i < len(ptrs); i++ {
+		if pool.New() == nil {
+			t.Fatal("alloc after trim failed")
+		}
+	}
+	if n := pool.Trim(0); n != 0 {
+		t.Fatalf("trimmed %d bytes of live slabs", n)
+	}
+}
+
+func TestArenaReleaseForEachLive(t *testing.T) {
+	pool := &MemPool[depthRecord]{}
+	if err := pool.InitArena(64); err != nil {
+		t.Fatal(err)
+	}
+	pool.New()
+	if err := pool.Release(); err != nil {
+		t.Fatal(err)
+	}
+	pool.ForEachLive(func(int64, *depthRecord) bool {
+		t.Fatal("released pool has no live slots")
+		return false
+	})
+}
+
+func TestArenaReleaseWhileTrimming(t *testing.T) {
+	pool := &MemPool[depthRecord]{}
+	if err := pool.InitArena(1024); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		pool.TrimEvery(ctx, time.Microsecond, 0)
+		close(done)
+	}()
+	time.Sleep(time.Millisecond)
+	if err := pool.Release(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	if n := pool.Trim(0); n != 0 {
+		t.Fatalf("trimmed %d bytes of a released arena", n)
+	}
+	cancel()
+	<-done
+}
@@ -0,0 +1,31 @@
+//go:build unix
+
+package mempool
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+func mapArena[T any](size int64) ([]T, error) {
+	var t T
+	length := int(size) * int(unsafe.Sizeof(t))
+	mem, err := unix.Mmap(-1, 0, length, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_ANON|unix.MAP_PRIVATE)
+	if err != nil {
+		return nil, err
+	}
+	return unsafe.Slice((*T)(unsafe.Pointer(&mem[0])), size), nil
+}
+
+func unmapArena[T any](slab []T) error {
+	if len(slab) == 0 {
+		return nil
+	}
+	length := len(slab) * int(unsafe.Sizeof(slab[0]))
+	return unix.Munmap(unsafe.Slice((*byte)(unsafe.Pointer(&slab[0])), length))
+}
+
+func adviseFree(mem []byte) error {
+	return unix.Madvise(mem, unix.MADV_DONTNEED)
+}
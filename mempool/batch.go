@@ -0,0 +1,84 @@
+package mempool
+
+import "runtime"
+
+// batchQueue is implemented by free lists that can move several indexes
+// with a single reservation.
+type batchQueue interface {
+	PushN([]int64) int
+	PopN([]int64) int
+}
+
+const batchChunk = 64
+
+// NewN fills dst with newly allocated objects and returns how many it got.
+// Free lists that implement batch operations reserve each chunk of up to 64
+// slots with a single CAS.
+func (m *Pool[T, Q, PQ]) NewN(dst []*T) int {
+	bq, ok := any(PQ(&m.queue)).(batchQueue)
+	if !ok {
+		for i := range dst {
+			if dst[i] = m.New(); dst[i] == nil {
+				return i
+			}
+		}
+		return len(dst)
+	}
+	var idxs [batchChunk]int64
+	got := 0
+	for got < len(dst) {
+		want := len(dst) - got
+		if want > batchChunk {
+			want = batchChunk
+		}
+		n := bq.PopN(idxs[:want])
+		if n == 0 {
+			m.stats.allocFailed.Add(1)
+			break
+		}
+		for _, idx := range idxs[:n] {
+			dst[got] = m.take(idx)
+			got++
+		}
+	}
+	return got
+}
+
+// FreeN returns every object in ptrs to the pool and reports how many were
+// accepted; pointers that Free would reject are skipped.
+func (m *Pool[T, Q, PQ]) FreeN(ptrs []*T) int {
+	bq, ok := any(PQ(&m.queue)).(batchQueue)
+	if !ok {
+		freed := 0
+		for _, ptr := range ptrs {
+			if m.Free(ptr) {
+				freed++
+			}
+		}
+		return freed
+	}
+	var idxs [batchChunk]int64
+	freed := 0
+	for len(ptrs) > 0 {
+		n := 0
+		for len(ptrs) > 0 && n < batchChunk {
+			if idx, ok := m.release(ptrs[0]); ok {
+				idxs[n] = idx
+				n++
+			}
+			ptrs = ptrs[1:]
+		}
+		for pushed := 0; pushed < n; {
+			k := bq.PushN(idxs[pushed:n])
+			if k == 0 {
+				runtime.Gosched()
+			}
+			pushed += k
+		}
+		for i := 0; i < n; i++ {
+			m.stats.free()
+		}
+		freed += n
+	}
+	return freed
+}
@@ -0,0 +1,68 @@
+package mempool
+
+import (
+	"sync"
+	"testing"
+)
+
+func testBatchPool(t *testing.T, pool iBatchPool[object16]) {
+	const size = 256
+	pool.Init(size)
+	ptrs := make([]*object16, size+10)
+	if n := pool.NewN(ptrs); n != size {
+		t.Fatalf("allocated %d, expected %d", n, size)
+	}
+	seen := make(map[*object16]bool, size)
+	for _, ptr := range ptrs[:size] {
+		if seen[ptr] {
+			t.Fatalf("slot %p handed out twice", ptr)
+		}
+		seen[ptr] = true
+	}
+	if n := pool.FreeN(append(ptrs[:size:size], ptrs[0])); n != size {
+		t.Fatalf("freed %d, expected %d", n, size)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			ptrs := make([]*object16, 16+w)
+			for i := 0; i < 2000; i++ {
+				n := pool.NewN(ptrs)
+				for _, ptr := range ptrs[:n] {
+					if ptr.Require() != 1 {
+						t.Errorf("slot %p shared between batches", ptr)
+					}
+				}
+				for _, ptr := range ptrs[:n] {
+					ptr.Release()
+				}
+				if pool.FreeN(ptrs[:n]) != n {
+					t.Errorf("free batch of %d failed", n)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	if n := pool.NewN(ptrs); n != size {
+		t.Fatalf("leaked slots: allocated %d of %d", n, size)
+	}
+}
+
+func TestBatchMemPool(t *testing.T) {
+	testBatchPool(t, new(MemPool[object16]))
+}
+
+func TestBatchChMemPool(t *testing.T) {
+	testBatchPool(t, new(ChMemPool[object16]))
+}
+
+func TestBatchStackMemPool(t *testing.T) {
+	testBatchPool(t, new(StackMemPool[object16]))
+}
+
+func TestBatchMPMCPool(t *testing.T) {
+	testBatchPool(t, new(MPMCMemPool[object16]))
+}
@@ -0,0 +1,157 @@
+package mempool
+
+import (
+	"fmt"
+	"math/bits"
+	"unsafe"
+)
+
+const (
+	minBufferShift = 6
+	maxBufferShift = 16
+)
+
+type bufferClass interface {
+	init(slots int64)
+	get() []byte
+	put(b []byte) bool
+	stats() PoolStats
+}
+
+type arrayClass[A any] struct {
+	pool  MemPool[A]
+	size  int
+	slots int64
+}
+
+func (c *arrayClass[A]) init(slots int64) {
+	var a A
+	c.size = int(unsafe.Sizeof(a))
+	c.slots = slots
+	if slots > 0 {
+		c.pool.Init(slots)
+	}
+}
+
+func (c *arrayClass[A]) get() []byte {
+	if c.slots == 0 {
+		c.pool.stats.allocFailed.Add(1)
+		return nil
+	}
+	ptr := c.pool.New()
+	if ptr == nil {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(ptr)), c.size)
+}
+
+func (c *arrayClass[A]) put(b []byte) bool {
+	ptr := (*A)(unsafe.Pointer(unsafe.SliceData(b)))
+	if c.slots == 0 || !c.pool.cache.owns(ptr) {
+		return false
+	}
+	return c.pool.Free(ptr)
+}
+
+func (c *arrayClass[A]) stats() PoolStats {
+	if c.slots == 0 {
+		return c.pool.stats.snapshot(0, QueueStats{})
+	}
+	return c.pool.Stats()
+}
+
+type BufferClassStats struct {
+	Size int
+	PoolStats
+}
+
+// BufferPool serves byte slices from power-of-two size classes between 64B
+// and 64KB. Larger requests, and requests made while a class is exhausted,
+// fall back to the heap.
+type BufferPool struct {
+	classes [BufferClasses]bufferClass
+}
+
+// BufferClasses is the number of size classes in a BufferPool.
+const BufferClasses = maxBufferShift - minBufferShift + 1
+
+// Init splits budget bytes evenly across the size classes, so small classes
+// get many more slots than large ones. A class too small for one buffer is
+// served from the heap.
+func (p *BufferPool) Init(budget int64) {
+	var slots [BufferClasses]int64
+	for i := range slots {
+		slots[i] = budget / BufferClasses >> (i + minBufferShift)
+	}
+	p.InitClasses(slots[:]...)
+}
+
+// InitClasses sets the number of slots of each class, smallest class first.
+// Classes past the end of slots get none.
+func (p *BufferPool) InitClasses(slots ...int64) {
+	if len(slots) > BufferClasses {
+		panic(fmt.Sprintf("mempool: %d buffer classes, got %d slot counts", BufferClasses, len(slots)))
+	}
+	p.classes = [...]bufferClass{
+		&arrayClass[[1 << 6]byte]{},
+		&arrayClass[[1 << 7]byte]{},
+		&arrayClass[[1 << 8]byte]{},
+		&arrayClass[[1 << 9]byte]{},
+		&arrayClass[[1 << 10]byte]{},
+		&arrayClass[[1 << 11]byte]{},
+		&arrayClass[[1 << 12]byte]{},
+		&arrayClass[[1 << 13]byte]{},
+		&arrayClass[[1 << 14]byte]{},
+		&arrayClass[[1 << 15]byte]{},
+		&arrayClass[[1 << 16]byte]{},
+	}
+	for i, c := range p.classes {
+		var n int64
+		if i < len(slots) {
+			n = slots[i]
+		}
+		c.init(n)
+	}
+}
+
+func bufferShift(n int) int {
+	if n <= 1<<minBufferShift {
+		return minBufferShift
+	}
+	return bits.Len(uint(n - 1))
+}
+
+// Get returns a slice of length n whose capacity is the size of its class.
+func (p *BufferPool) Get(n int) []byte {
+	shift := bufferShift(n)
+	if shift > maxBufferShift {
+		return make([]byte, n)
+	}
+	b := p.classes[shift-minBufferShift].get()
+	if b == nil {
+		return make([]byte, n, 1<<shift)
+	}
+	return b[:n]
+}
+
+// Put returns b to its class and reports whether b came from this pool and
+// was not already returned.
+func (p *BufferPool) Put(b []byte) bool {
+	c := cap(b)
+	if c == 0 || c&(c-1) != 0 {
+		return false
+	}
+	shift := bits.TrailingZeros(uint(c))
+	if shift < minBufferShift || shift > maxBufferShift {
+		return false
+	}
+	return p.classes[shift-minBufferShift].put(b[:c])
+}
+
+func (p *BufferPool) Stats() []BufferClassStats {
+	stats := make([]BufferClassStats, len(p.classes))
+	for i, c := range p.classes {
+		stats[i] = BufferClassStats{Size: 1 << (i + minBufferShift), PoolStats: c.stats()}
+	}
+	return stats
+}
@@ -0,0 +1,83 @@
+package mempool
+
+import "testing"
+
+func TestBufferPool(t *testing.T) {
+	pool := &BufferPool{}
+	pool.InitClasses(2, 2, 2)
+
+	b := pool.Get(100)
+	if len(b) != 100 || cap(b) != 128 {
+		t.Fatalf("len=%d cap=%d", len(b), cap(b))
+	}
+	if !pool.Put(b[:10]) {
+		t.Fatal("put pooled buffer failed")
+	}
+	if pool.Put(b) {
+		t.Fatal("double put should fail")
+	}
+	if pool.Put(make([]byte, 128)) {
+		t.Fatal("foreign buffer should be rejected")
+	}
+	if pool.Put(pool.Get(256)[64:]) {
+		t.Fatal("interior slice should be rejected")
+	}
+
+	if b := pool.Get(1 << 20); len(b) != 1<<20 || pool.Put(b) {
+		t.Fatal("oversize buffer should come from the heap")
+	}
+
+	pool.Get(1)
+	pool.Get(64)
+	if b := pool.Get(10); cap(b) != 64 || pool.Put(b) {
+		t.Fatal("exhausted class should fall back to the heap")
+	}
+
+	stats := pool.Stats()
+	if stats[0].Size != 64 || stats[0].InUse != 2 || stats[0].AllocFailed != 1 {
+		t.Fatalf("unexpected class stats: %+v", stats[0])
+	}
+	if stats[1].Size != 128 || stats[1].InUse != 0 || stats[1].HighWater != 1 {
+		t.Fatalf("unexpected class stats: %+v", stats[1])
+	}
+}
+
+func TestBufferPoolBudget(t *testing.T) {
+	pool := &BufferPool{}
+	pool.Init(BufferClasses << 12)
+	stats := pool.Stats()
+	if stats[0].Capacity != 64 || stats[6].Capacity != 1 || stats[7].Capacity != 0 {
+		t.Fatalf("unexpected class capacities: %d %d %d", stats[0].Capacity, stats[6].Capacity, stats[7].Capacity)
+	}
+	if b := pool.Get(5000); cap(b) != 8192 || pool.Put(b) {
+		t.Fatal("empty class should fall back to the heap")
+	}
+	if pool.Stats()[7].AllocFailed != 1 {
+		t.Fatal("empty class should count the failed alloc")
+	}
+}
+
+func BenchmarkBufferPool(b *testing.B) {
+	pool := &BufferPool{}
+	pool.Init(8 << 20)
+	b.RunParallel(func(pb *testing.PB) {
+		n := 0
+		for pb.Next() {
+			n = (n + 97) % 8192
+			buf := pool.Get(n + 1)
+			buf[0] = 1
+			pool.Put(buf)
+		}
+	})
+}
+
+func BenchmarkBufferHeap(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		n := 0
+		for pb.Next() {
+			n = (n + 97) % 8192
+			buf := make([]byte, n+1)
+			buf[0] = 1
+		}
+	})
+}
@@ -2,16 +2,38 @@ package mempool
 
 import (
 	"reflect"
-	"sync/atomic"
 	"unsafe"
+
+	"hf-utils/vector"
+)
+
+// Resetter is implemented by pooled types that clear their own state. Reset
+// always runs after any zeroing: on Free, or on New under ZeroOnNew, so the
+// zeroing never undoes it.
+type Resetter interface {
+	Reset()
+}
+
+type ZeroPolicy int
+
+const (
+	ZeroNever ZeroPolicy = iota
+	ZeroOnFree
+	ZeroOnNew
 )
 
 type bitmapCache[T any] struct {
 	cache    []T
-	tag      []atomic.Bool
+	tag      vector.AtomicBitset
 	size     int64
 	header   uintptr
 	elemSize uintptr
+	zero     ZeroPolicy
+	resetter bool
+	arena    bool
+
+	slabs     []arenaSlab
+	slabSlots int64
 }
 
 func (c *bitmapCache[T]) getIndex(t *T) uintptr {
@@ -19,11 +41,53 @@ func (c *bitmapCache[T]) getIndex(t *T) uintptr {
 	return (ptr - c.header) / c.elemSize
 }
 
+// owns reports whether t points at the start of a slot in this cache.
+func (c *bitmapCache[T]) owns(t *T) bool {
+	ptr := uintptr(unsafe.Pointer(t))
+	if ptr < c.header || (ptr-c.header)%c.elemSize != 0 {
+		return false
+	}
+	return int64(c.getIndex(t)) < c.size
+}
+
 func (c *bitmapCache[T]) init(size int64) {
-	c.cache = make([]T, size)
-	c.tag = make([]atomic.Bool, size)
+	c.initSlab(make([]T, size))
+}
+
+func (c *bitmapCache[T]) initSlab(slab []T) {
+	size := int64(len(slab))
+	c.cache = slab
+	c.tag = *vector.NewAtomicBitset(int(size))
 	c.size = size
+	c.arena = false
+	c.slabs = nil
 	c.header = uintptr(unsafe.Pointer(&c.cache[0]))
 	var t T
 	c.elemSize = reflect.TypeOf(t).Size()
+	_, c.resetter = any(&t).(Resetter)
+}
+
+// acquire prepares slot idx for a caller of New.
+func (c *bitmapCache[T]) acquire(idx int64) *T {
+	ptr := &c.cache[idx]
+	if c.zero == ZeroOnNew {
+		var t T
+		*ptr = t
+		if c.resetter {
+			any(ptr).(Resetter).Reset()
+		}
+	}
+	return ptr
+}
+
+// recycle clears slot idx once its tag is released and before it is queued.
+func (c *bitmapCache[T]) recycle(idx int64) {
+	ptr := &c.cache[idx]
+	if c.zero == ZeroOnFree {
+		var t T
+		*ptr = t
+	}
+	if c.resetter && c.zero != ZeroOnNew {
+		any(ptr).(Resetter).Reset()
+	}
 }
@@ -58,3 +58,59 @@ func TestSliceHeader(t *testing.T) {
 	t.Logf("head: %d", getSliceHead(array))
 	t.Logf("cap: %d", cap(array))
 }
+
+type resetRecord struct {
+	Price  int64
+	Orders []int64
+}
+
+func (r *resetRecord) Reset() {
+	r.Price = -1
+	r.Orders = r.Orders[:0]
+}
+
+func TestZeroPolicy(t *testing.T) {
+	for _, policy := range []ZeroPolicy{ZeroOnFree, ZeroOnNew} {
+		pool := &MemPool[object16]{}
+		pool.SetZeroPolicy(policy)
+		pool.Init(1)
+		ptr := pool.New()
+		ptr.Idx = 7
+		ptr.Data[0] = 1
+		pool.Free(ptr)
+		ptr = pool.New()
+		if ptr.Idx != 0 || ptr.Data[0] != 0 {
+			t.Fatalf("policy %d: stale slot %+v", policy, *ptr)
+		}
+	}
+}
+
+func TestResetter(t *testing.T) {
+	pool := &ChMemPool[resetRecord]{}
+	pool.Init(1)
+	ptr := pool.New()
+	ptr.Price = 100
+	ptr.Orders = append(ptr.Orders, 1, 2, 3)
+	pool.Free(ptr)
+	ptr = pool.New()
+	if ptr.Price != -1 || len(ptr.Orders) != 0 || cap(ptr.Orders) == 0 {
+		t.Fatalf("slot not reset: %+v", *ptr)
+	}
+}
+
+func TestResetterZeroOnNew(t *testing.T) {
+	pool := &MemPool[resetRecord]{}
+	pool.SetZeroPolicy(ZeroOnNew)
+	pool.Init(1)
+	ptr := pool.New()
+	if ptr.Price != -1 {
+		t.Fatalf("new slot not reset: %+v", *ptr)
+	}
+	ptr.Price = 100
+	ptr.Orders = append(ptr.Orders, 1, 2, 3)
+	pool.Free(ptr)
+	ptr = pool.New()
+	if ptr.Price != -1 || len(ptr.Orders) != 0 {
+		t.Fatalf("slot not zeroed then reset: %+v", *ptr)
+	}
+}
@@ -0,0 +1,150 @@
+package mempool
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"unsafe"
+
+	"hf-utils/vector"
+)
+
+const checkpointMagic uint64 = 0x31504b43504d4648 // "HFMPCKP1"
+
+type checkpointHeader struct {
+	Magic    uint64
+	ElemSize uint64
+	Capacity uint64
+	Count    uint64
+}
+
+// ForEachLive calls fn for every allocated slot in index order until fn
+// returns false. Slots allocated or freed concurrently may or may not be seen.
+func (m *Pool[T, Q, PQ]) ForEachLive(fn func(idx int64, ptr *T) bool) {
+	for i := m.cache.tag.NextSet(0); i >= 0; i = m.cache.tag.NextSet(i + 1) {
+		if !fn(int64(i), &m.cache.cache[i]) {
+			return
+		}
+	}
+}
+
+func checkpointType[T any]() error {
+	var t T
+	if typ := reflect.TypeOf(t); HasPointers(typ) {
+		return fmt.Errorf("checkpoint: type %s contains pointers", typ)
+	}
+	return nil
+}
+
+// Checkpoint writes every live slot with its index to w. Objects must not be
+// modified while the checkpoint runs.
+func (m *Pool[T, Q, PQ]) Checkpoint(w io.Writer) error {
+	if err := checkpointType[T](); err != nil {
+		return err
+	}
+	var count uint64
+	m.ForEachLive(func(int64, *T) bool {
+		count++
+		return true
+	})
+	bw := bufio.NewWriter(w)
+	header := checkpointHeader{
+		Magic:    checkpointMagic,
+		ElemSize: uint64(m.cache.elemSize),
+		Capacity: uint64(m.cache.size),
+		Count:    count,
+	}
+	if err := binary.Write(bw, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+	var err error
+	var idx [8]byte
+	m.ForEachLive(func(i int64, ptr *T) bool {
+		if count == 0 {
+			return false
+		}
+		count--
+		binary.LittleEndian.PutUint64(idx[:], uint64(i))
+		if _, err = bw.Write(idx[:]); err != nil {
+			return false
+		}
+		_, err = bw.Write(unsafe.Slice((*byte)(unsafe.Pointer(ptr)), m.cache.elemSize))
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	if count != 0 {
+		return fmt.Errorf("checkpoint: %d slots freed while writing", count)
+	}
+	return bw.Flush()
+}
+
+// Restore loads a checkpoint into a freshly initialized pool with the same
+// capacity, putting every object back in its original slot. It must be
+// called before the pool is shared.
+func (m *Pool[T, Q, PQ]) Restore(r io.Reader) error {
+	if err := checkpointType[T](); err != nil {
+		return err
+	}
+	if m.stats.inUse.Load() != 0 {
+		return fmt.Errorf("restore: pool has %d live objects", m.stats.inUse.Load())
+	}
+	br := bufio.NewReader(r)
+	var header checkpointHeader
+	if err := binary.Read(br, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+	if header.Magic != checkpointMagic {
+		return fmt.Errorf("restore: not a pool checkpoint")
+	}
+	if header.ElemSize != uint64(m.cache.elemSize) || header.Capacity != uint64(m.cache.size) {
+		return fmt.Errorf(
+			"restore: checkpoint holds %d slots of %d bytes, pool has %d of %d",
+			header.Capacity, header.ElemSize, m.cache.size, m.cache.elemSize,
+		)
+	}
+
+	if header.Count > header.Capacity {
+		return fmt.Errorf("restore: %d records for %d slots", header.Count, header.Capacity)
+	}
+
+	// Read and check everything before touching the pool, so a short or bad
+	// stream leaves it as it was.
+	seen := vector.NewBitset(int(m.cache.size))
+	slots := make([]int64, header.Count)
+	records := make([]byte, header.Count*header.ElemSize)
+	var idx [8]byte
+	for n := range slots {
+		if _, err := io.ReadFull(br, idx[:]); err != nil {
+			return err
+		}
+		i := int64(binary.LittleEndian.Uint64(idx[:]))
+		if i < 0 || i >= m.cache.size || seen.TestAndSet(int(i)) {
+			return fmt.Errorf("restore: invalid slot %d", i)
+		}
+		slots[n] = i
+		if _, err := io.ReadFull(br, records[uint64(n)*header.ElemSize:uint64(n+1)*header.ElemSize]); err != nil {
+			return err
+		}
+	}
+
+	for n, i := range slots {
+		if m.cache.slabs != nil {
+			m.cache.claimSlab(i)
+		}
+		ptr := &m.cache.cache[i]
+		copy(unsafe.Slice((*byte)(unsafe.Pointer(ptr)), m.cache.elemSize), records[uint64(n)*header.ElemSize:])
+		m.cache.tag.Set(int(i))
+		m.stats.alloc()
+	}
+
+	queue := PQ(&m.queue)
+	queue.Init(m.cache.size)
+	for i := m.cache.tag.NextClear(0); i >= 0; i = m.cache.tag.NextClear(i + 1) {
+		queue.Push(int64(i))
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package mempool
+
+import (
+	"bytes"
+	"testing"
+)
+
+type orderRecord struct {
+	ID     int64
+	Price  int64
+	Volume int64
+}
+
+func TestForEachLive(t *testing.T) {
+	pool := &MemPool[orderRecord]{}
+	pool.Init(8)
+	ptrs := make([]*orderRecord, 5)
+	for i := range ptrs {
+		ptrs[i] = pool.New()
+	}
+	pool.Free(ptrs[1])
+	pool.Free(ptrs[3])
+
+	var seen []int64
+	pool.ForEachLive(func(idx int64, ptr *orderRecord) bool {
+		seen = append(seen, idx)
+		return len(seen) < 2
+	})
+	if len(seen) != 2 || seen[0] != 0 || seen[1] != 2 {
+		t.Fatalf("unexpected live slots %v", seen)
+	}
+}
+
+func TestCheckpointRestore(t *testing.T) {
+	pool := &StackMemPool[orderRecord]{}
+	pool.Init(16)
+	live := map[int64]orderRecord{}
+	for i := int64(0); i < 10; i++ {
+		ptr := pool.New()
+		*ptr = orderRecord{ID: i, Price: 100 + i, Volume: i * 10}
+		if i%3 == 0 {
+			pool.Free(ptr)
+		}
+	}
+	pool.ForEachLive(func(idx int64, ptr *orderRecord) bool {
+		live[idx] = *ptr
+		return true
+	})
+
+	var buf bytes.Buffer
+	if err := pool.Checkpoint(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := &StackMemPool[orderRecord]{}
+	restored.Init(16)
+	if err := restored.Restore(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	restored.ForEachLive(func(idx int64, ptr *orderRecord) bool {
+		count++
+		if live[idx] != *ptr {
+			t.Errorf("slot %d: got %+v, expected %+v", idx, *ptr, live[idx])
+		}
+		return true
+	})
+	if count != len(live) || restored.Stats().InUse != int64(len(live)) {
+		t.Fatalf("restored %d objects, expected %d", count, len(live))
+	}
+	for i := len(live); i < 16; i++ {
+		if restored.New() == nil {
+			t.Fatalf("free slot %d missing after restore", i)
+		}
+	}
+	if restored.New() != nil {
+		t.Fatal("restored pool handed out a live slot")
+	}
+
+	truncated := &StackMemPool[orderRecord]{}
+	truncated.Init(16)
+	if err := truncated.Restore(bytes.NewReader(buf.Bytes()[:buf.Len()-4])); err == nil {
+		t.Fatal("restore of a truncated checkpoint should fail")
+	}
+	if n := truncated.Stats().InUse; n != 0 {
+		t.Fatalf("failed restore left %d objects in use", n)
+	}
+	for i := 0; i < 16; i++ {
+		if truncated.New() == nil {
+			t.Fatalf("slot %d lost after failed restore", i)
+		}
+	}
+
+	small := &StackMemPool[orderRecord]{}
+	small.Init(8)
+	if err := small.Restore(bytes.NewReader(buf.Bytes())); err == nil {
+		t.Fatal("restore into a different capacity should fail")
+	}
+	if err := new(MemPool[resetRecord]).Checkpoint(&buf); err == nil {
+		t.Fatal("checkpoint of pointer type should fail")
+	}
+}
+
+func TestForEachLiveUninitialized(t *testing.T) {
+	pool := &MemPool[orderRecord]{}
+	pool.ForEachLive(func(int64, *orderRecord) bool {
+		t.Fatal("uninitialized pool has no live slots")
+		return false
+	})
+	var buf bytes.Buffer
+	if err := pool.Checkpoint(&buf); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,7 +1,12 @@
 package mempool
 
+import "sync/atomic"
+
 type chQueue struct {
 	ch chan int64
+
+	pushFailed atomic.Int64
+	popFailed  atomic.Int64
 }
 
 func (q *chQueue) Init(size int64) {
@@ -13,6 +18,7 @@ func (q *chQueue) Push(idx int64) bool {
 	case q.ch <- idx:
 		return true
 	default:
+		q.pushFailed.Add(1)
 		return false
 	}
 }
@@ -22,38 +28,16 @@ func (q *chQueue) Pop() (idx int64, ok bool) {
 	case idx = <-q.ch:
 		return idx, true
 	default:
+		q.popFailed.Add(1)
 		return -1, false
 	}
 }
 
-type ChMemPool[T any] struct {
-	queue chQueue
-	cache bitmapCache[T]
-}
-
-func (m *ChMemPool[T]) Init(size int64) {
-	m.cache.init(size)
-	m.queue.Init(size)
-	for i := int64(0); i < size; i++ {
-		m.queue.Push(i)
-	}
-}
-
-func (m *ChMemPool[T]) New() *T {
-	idx, ok := m.queue.Pop()
-	if ok {
-		m.cache.tag[idx].Store(true)
-		return &m.cache.cache[idx]
-	}
-	return nil
-}
-
-func (m *ChMemPool[T]) Free(ptr *T) bool {
-	idx := int64(m.cache.getIndex(ptr))
-	if idx < m.cache.size {
-		if m.cache.tag[idx].CompareAndSwap(true, false) {
-			return m.queue.Push(idx)
-		}
+func (q *chQueue) Stats() QueueStats {
+	return QueueStats{
+		Capacity:   int64(cap(q.ch)),
+		Len:        int64(len(q.ch)),
+		PushFailed: q.pushFailed.Load(),
+		PopFailed:  q.popFailed.Load(),
 	}
-	return false
 }
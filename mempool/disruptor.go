@@ -0,0 +1,230 @@
+package mempool
+
+import (
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Sequence is a cache-line padded counter of the last slot a producer
+// published or a consumer finished with.
+type Sequence struct {
+	_     cacheLinePad
+	value atomic.Int64
+	_     cacheLinePad
+}
+
+func (s *Sequence) Load() int64 {
+	return s.value.Load()
+}
+
+func minSequence(seqs []*Sequence) int64 {
+	m := int64(math.MaxInt64)
+	for _, s := range seqs {
+		if v := s.value.Load(); v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// WaitStrategy decides how a producer or consumer waits until every sequence
+// in deps has reached seq. Wait returns the lowest dependent sequence, and
+// false if closed was set before seq became available.
+type WaitStrategy interface {
+	Wait(seq int64, deps []*Sequence, closed *atomic.Bool) (int64, bool)
+	Signal()
+}
+
+type BusySpinWait struct{}
+
+func (BusySpinWait) Wait(seq int64, deps []*Sequence, closed *atomic.Bool) (int64, bool) {
+	for {
+		avail := minSequence(deps)
+		if avail >= seq {
+			return avail, true
+		}
+		if closed.Load() {
+			return avail, false
+		}
+	}
+}
+
+func (BusySpinWait) Signal() {}
+
+type YieldWait struct{}
+
+func (YieldWait) Wait(seq int64, deps []*Sequence, closed *atomic.Bool) (int64, bool) {
+	for {
+		avail := minSequence(deps)
+		if avail >= seq {
+			return avail, true
+		}
+		if closed.Load() {
+			return avail, false
+		}
+		runtime.Gosched()
+	}
+}
+
+func (YieldWait) Signal() {}
+
+// BlockingWait parks waiters on a condition variable. It saves CPU at the
+// price of a mutex on every Signal.
+type BlockingWait struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+}
+
+func NewBlockingWait() *BlockingWait {
+	w := &BlockingWait{}
+	w.cond = sync.NewCond(&w.mu)
+	return w
+}
+
+func (w *BlockingWait) Wait(seq int64, deps []*Sequence, closed *atomic.Bool) (int64, bool) {
+	if avail := minSequence(deps); avail >= seq {
+		return avail, true
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for {
+		avail := minSequence(deps)
+		if avail >= seq {
+			return avail, true
+		}
+		if closed.Load() {
+			return avail, false
+		}
+		w.cond.Wait()
+	}
+}
+
+func (w *BlockingWait) Signal() {
+	w.mu.Lock()
+	w.cond.Broadcast()
+	w.mu.Unlock()
+}
+
+// Disruptor is a pre-allocated ring where a single producer publishes events
+// and every consumer reads every event. Consumers may depend on other
+// consumers, and the producer never laps the slowest one.
+type Disruptor[T any] struct {
+	buf       []T
+	mask      int64
+	cursor    Sequence
+	next      int64
+	wait      WaitStrategy
+	consumers []*Sequence
+	closed    atomic.Bool
+}
+
+// NewDisruptor creates a ring of the next power of two >= size slots.
+func NewDisruptor[T any](size int64, wait WaitStrategy) *Disruptor[T] {
+	capacity := int64(1)
+	for capacity < size {
+		capacity <<= 1
+	}
+	d := &Disruptor[T]{
+		buf:  make([]T, capacity),
+		mask: capacity - 1,
+		wait: wait,
+	}
+	d.cursor.value.Store(-1)
+	return d
+}
+
+// NewConsumer registers a consumer that only sees an event after every one
+// of deps has processed it. All consumers must be registered before the
+// first Next.
+func (d *Disruptor[T]) NewConsumer(deps ...*Consumer[T]) *Consumer[T] {
+	c := &Consumer[T]{d: d}
+	c.seq.value.Store(-1)
+	if len(deps) == 0 {
+		c.deps = []*Sequence{&d.cursor}
+	} else {
+		for _, dep := range deps {
+			c.deps = append(c.deps, &dep.seq)
+		}
+	}
+	d.consumers = append(d.consumers, &c.seq)
+	return c
+}
+
+// Next claims the next slot, waiting while it is still held by the slowest
+// consumer. It returns false if the disruptor was closed while waiting.
+func (d *Disruptor[T]) Next() (int64, *T, bool) {
+	seq := d.next
+	if _, ok := d.wait.Wait(seq-int64(len(d.buf)), d.consumers, &d.closed); !ok {
+		return seq, nil, false
+	}
+	d.next++
+	return seq, &d.buf[seq&d.mask], true
+}
+
+// TryNext claims the next slot only if no consumer is holding it.
+func (d *Disruptor[T]) TryNext() (int64, *T, bool) {
+	seq := d.next
+	if minSequence(d.consumers) < seq-int64(len(d.buf)) {
+		return seq, nil, false
+	}
+	d.next++
+	return seq, &d.buf[seq&d.mask], true
+}
+
+// Publish makes every slot up to seq visible to consumers.
+func (d *Disruptor[T]) Publish(seq int64) {
+	d.cursor.value.Store(seq)
+	d.wait.Signal()
+}
+
+func (d *Disruptor[T]) Cursor() int64 {
+	return d.cursor.Load()
+}
+
+// Close wakes every waiter. Consumers still drain events published before
+// Close.
+func (d *Disruptor[T]) Close() {
+	d.closed.Store(true)
+	d.wait.Signal()
+}
+
+type Consumer[T any] struct {
+	d    *Disruptor[T]
+	seq  Sequence
+	deps []*Sequence
+}
+
+func (c *Consumer[T]) Sequence() int64 {
+	return c.seq.Load()
+}
+
+// Poll waits for at least one new event and passes every available one to
+// handler in order. It returns false once the disruptor is closed and this
+// consumer has processed everything published before Close.
+func (c *Consumer[T]) Poll(handler func(seq int64, event *T)) bool {
+	for {
+		next := c.seq.value.Load() + 1
+		avail, ok := c.d.wait.Wait(next, c.deps, &c.d.closed)
+		if avail >= next {
+			for seq := next; seq <= avail; seq++ {
+				handler(seq, &c.d.buf[seq&c.d.mask])
+			}
+			c.seq.value.Store(avail)
+			c.d.wait.Signal()
+			return true
+		}
+		if !ok && next > c.d.cursor.Load() {
+			return false
+		}
+		// closed, but a dependency is still draining
+		runtime.Gosched()
+	}
+}
+
+// Run polls until the disruptor is closed and drained.
+func (c *Consumer[T]) Run(handler func(seq int64, event *T)) {
+	for c.Poll(handler) {
+	}
+}
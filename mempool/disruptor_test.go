@@ -0,0 +1,147 @@
+package mempool
+
+import (
+	"sync"
+	"testing"
+)
+
+type tickEvent struct {
+	Seq       int64
+	Persisted bool
+}
+
+func testDisruptor(t *testing.T, wait WaitStrategy, total int64) {
+	d := NewDisruptor[tickEvent](64, wait)
+	persist := d.NewConsumer()
+	publish := d.NewConsumer(persist)
+	audit := d.NewConsumer()
+
+	var wg sync.WaitGroup
+	var published, audited int64
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		persist.Run(func(seq int64, ev *tickEvent) {
+			if ev.Seq != seq {
+				t.Errorf("persist got %d at %d", ev.Seq, seq)
+			}
+			ev.Persisted = true
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		publish.Run(func(seq int64, ev *tickEvent) {
+			if !ev.Persisted {
+				t.Errorf("event %d published before persisted", seq)
+			}
+			published++
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		audit.Run(func(seq int64, ev *tickEvent) {
+			if ev.Seq != seq {
+				t.Errorf("audit got %d at %d", ev.Seq, seq)
+			}
+			audited++
+		})
+	}()
+
+	for i := int64(0); i < total; i++ {
+		seq, ev, ok := d.Next()
+		if !ok {
+			t.Fatal("next failed")
+		}
+		ev.Seq = seq
+		ev.Persisted = false
+		d.Publish(seq)
+	}
+	d.Close()
+	wg.Wait()
+	if published != total || audited != total {
+		t.Fatalf("published=%d audited=%d, expected %d", published, audited, total)
+	}
+}
+
+func TestDisruptorBusySpin(t *testing.T) {
+	testDisruptor(t, BusySpinWait{}, 256)
+}
+
+func TestDisruptorYield(t *testing.T) {
+	testDisruptor(t, YieldWait{}, 20000)
+}
+
+func TestDisruptorBlocking(t *testing.T) {
+	testDisruptor(t, NewBlockingWait(), 20000)
+}
+
+func TestDisruptorBackpressure(t *testing.T) {
+	d := NewDisruptor[int64](4, YieldWait{})
+	c := d.NewConsumer()
+	for i := 0; i < 4; i++ {
+		seq, ev, ok := d.TryNext()
+		if !ok {
+			t.Fatalf("slot %d should be free", i)
+		}
+		*ev = seq
+		d.Publish(seq)
+	}
+	if _, _, ok := d.TryNext(); ok {
+		t.Fatal("producer lapped the consumer")
+	}
+	c.Poll(func(int64, *int64) {})
+	if _, _, ok := d.TryNext(); !ok {
+		t.Fatal("slot should be free after consume")
+	}
+}
+
+func benchmarkDisruptor(b *testing.B, wait WaitStrategy) {
+	d := NewDisruptor[int64](1<<12, wait)
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		c := d.NewConsumer()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Run(func(int64, *int64) {})
+		}()
+	}
+	for i := 0; i < b.N; i++ {
+		seq, ev, _ := d.Next()
+		*ev = int64(i)
+		d.Publish(seq)
+	}
+	d.Close()
+	wg.Wait()
+}
+
+func BenchmarkDisruptorYield(b *testing.B) {
+	benchmarkDisruptor(b, YieldWait{})
+}
+
+func BenchmarkDisruptorBlocking(b *testing.B) {
+	benchmarkDisruptor(b, NewBlockingWait())
+}
+
+func BenchmarkChannelFanOut(b *testing.B) {
+	chs := make([]chan int64, 3)
+	var wg sync.WaitGroup
+	for i := range chs {
+		chs[i] = make(chan int64, 1<<12)
+		wg.Add(1)
+		go func(ch chan int64) {
+			defer wg.Done()
+			for range ch {
+			}
+		}(chs[i])
+	}
+	for i := 0; i < b.N; i++ {
+		for _, ch := range chs {
+			ch <- int64(i)
+		}
+	}
+	for _, ch := range chs {
+		close(ch)
+	}
+	wg.Wait()
+}
@@ -3,53 +3,146 @@ package mempool
 import (
 	"fmt"
 	"runtime"
+	"sync"
 )
 
+// IQueue is the free list a Pool keeps its unused slot indexes in. Push may
+// fail transiently; the pool retries until it succeeds.
 type IQueue interface {
 	Init(int64)
-	Push(int64)
+	Push(int64) bool
 	Pop() (int64, bool)
 }
 
-type MemPool[T any] struct {
-	queue casQueue
+// Pool is a fixed-size object pool whose free slots are tracked by Q.
+type Pool[T any, Q any, PQ interface {
+	*Q
+	IQueue
+}] struct {
+	queue Q
 	cache bitmapCache[T]
+	stats poolCounters
+
+	// arenaMu keeps Trim off the arena while Release unmaps it.
+	arenaMu sync.Mutex
+}
+
+// MemPool keeps free slots in a FIFO CAS queue.
+type MemPool[T any] struct {
+	Pool[T, casQueue, *casQueue]
+}
+
+// ChMemPool keeps free slots in a buffered channel.
+type ChMemPool[T any] struct {
+	Pool[T, chQueue, *chQueue]
+}
+
+// StackMemPool keeps free slots in a Treiber stack, so the most recently
+// freed (and most likely cache-hot) slot is handed out first.
+type StackMemPool[T any] struct {
+	Pool[T, treiberStack, *treiberStack]
 }
 
-func (m *MemPool[T]) Init(size int64) {
+// MPMCMemPool keeps free slots in a Queue, whose cells carry their own
+// sequence numbers.
+type MPMCMemPool[T any] struct {
+	Pool[T, Queue[int64], *Queue[int64]]
+}
+
+// CASQueue, ChanQueue and TreiberStack are the free lists behind MemPool,
+// ChMemPool and StackMemPool, exported so they can be benchmarked on their
+// own.
+type CASQueue struct{ casQueue }
+type ChanQueue struct{ chQueue }
+type TreiberStack struct{ treiberStack }
+
+// SetZeroPolicy controls whether slots are zeroed when freed or when handed
+// out again. It must be called before the pool is shared.
+func (m *Pool[T, Q, PQ]) SetZeroPolicy(policy ZeroPolicy) {
+	m.cache.zero = policy
+}
+
+func (m *Pool[T, Q, PQ]) Init(size int64) {
 	m.cache.init(size)
-	m.queue.Init(size)
+	m.initQueue(size)
+}
+
+// InitSlab is Init with caller-provided storage; the pool hands out the
+// elements of slab and never reallocates it.
+func (m *Pool[T, Q, PQ]) InitSlab(slab []T) {
+	m.cache.initSlab(slab)
+	m.initQueue(int64(len(slab)))
+}
+
+// Owns reports whether ptr points at a slot of this pool.
+func (m *Pool[T, Q, PQ]) Owns(ptr *T) bool {
+	return m.cache.owns(ptr)
+}
+
+func (m *Pool[T, Q, PQ]) initQueue(size int64) {
+	queue := PQ(&m.queue)
+	queue.Init(size)
 	for i := int64(0); i < size; i++ {
-		m.queue.Push(i)
+		queue.Push(i)
 	}
 }
 
-func (m *MemPool[T]) New() *T {
-	idx, ok := m.queue.Pop()
-	// idx, ok, w, r := m.queue.pop()
+func (m *Pool[T, Q, PQ]) New() *T {
+	idx, ok := PQ(&m.queue).Pop()
 	if ok {
-		if m.cache.tag[idx].Load() {
-
-			panic(fmt.Sprintf(
-				"cache[%d] not recycled",
-				idx,
-			))
-		}
-		m.cache.tag[idx].Store(true)
-		return &m.cache.cache[idx]
+		return m.take(idx)
 	}
+	m.stats.allocFailed.Add(1)
 	return nil
 }
 
-func (m *MemPool[T]) Free(ptr *T) bool {
+func (m *Pool[T, Q, PQ]) Free(ptr *T) bool {
+	idx, ok := m.release(ptr)
+	if !ok {
+		return false
+	}
+	for !PQ(&m.queue).Push(idx) {
+		runtime.Gosched()
+	}
+	m.stats.free()
+	return true
+}
+
+// take marks slot idx, just popped from the free list, as allocated.
+func (m *Pool[T, Q, PQ]) take(idx int64) *T {
+	if m.cache.tag.Test(int(idx)) {
+		panic(fmt.Sprintf(
+			"cache[%d] not recycled",
+			idx,
+		))
+	}
+	if m.cache.slabs != nil {
+		m.cache.claimSlab(idx)
+	}
+	m.cache.tag.Set(int(idx))
+	m.stats.alloc()
+	return m.cache.acquire(idx)
+}
+
+// release clears the tag of ptr and returns its slot index, or false if ptr
+// is not an allocated object of this pool.
+func (m *Pool[T, Q, PQ]) release(ptr *T) (int64, bool) {
 	idx := int64(m.cache.getIndex(ptr))
-	if idx < m.cache.size {
-		if m.cache.tag[idx].CompareAndSwap(true, false) {
-			for !m.queue.Push(idx) {
-				runtime.Gosched()
-			}
-			return true
+	if idx < m.cache.size && m.cache.tag.TestAndClear(int(idx)) {
+		m.cache.recycle(idx)
+		if m.cache.slabs != nil {
+			m.cache.releaseSlab(idx)
 		}
+		return idx, true
+	}
+	m.stats.freeFailed.Add(1)
+	return -1, false
+}
+
+func (m *Pool[T, Q, PQ]) Stats() PoolStats {
+	var qs QueueStats
+	if src, ok := any(PQ(&m.queue)).(QueueStatsSource); ok {
+		qs = src.Stats()
 	}
-	return false
+	return m.stats.snapshot(m.cache.size, qs)
 }
@@ -0,0 +1,144 @@
+package mempool
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type PoolStatsSource interface {
+	Stats() PoolStats
+}
+
+type QueueStatsSource interface {
+	Stats() QueueStats
+}
+
+type metricDesc struct {
+	name  string
+	help  string
+	kind  string
+	value func(any) int64
+}
+
+var poolMetrics = []metricDesc{
+	{"mempool_capacity", "Number of slots in the pool.", "gauge", func(s any) int64 { return s.(PoolStats).Capacity }},
+	{"mempool_in_use", "Number of slots currently allocated.", "gauge", func(s any) int64 { return s.(PoolStats).InUse }},
+	{"mempool_high_water", "Maximum number of slots allocated at once.", "gauge", func(s any) int64 { return s.(PoolStats).HighWater }},
+	{"mempool_alloc_failed_total", "New calls that found the pool exhausted.", "counter", func(s any) int64 { return s.(PoolStats).AllocFailed }},
+	{"mempool_free_failed_total", "Free calls rejected by the pool.", "counter", func(s any) int64 { return s.(PoolStats).FreeFailed }},
+	{"mempool_cas_retries_total", "CAS retries on the free queue.", "counter", func(s any) int64 { return s.(PoolStats).CasRetries }},
+}
+
+var queueMetrics = []metricDesc{
+	{"mempool_queue_capacity", "Number of slots in the queue.", "gauge", func(s any) int64 { return s.(QueueStats).Capacity }},
+	{"mempool_queue_len", "Number of items currently queued.", "gauge", func(s any) int64 { return s.(QueueStats).Len }},
+	{"mempool_queue_push_failed_total", "Push calls that found the queue full.", "counter", func(s any) int64 { return s.(QueueStats).PushFailed }},
+	{"mempool_queue_pop_failed_total", "Pop calls that found the queue empty.", "counter", func(s any) int64 { return s.(QueueStats).PopFailed }},
+	{"mempool_queue_push_retries_total", "CAS retries on push.", "counter", func(s any) int64 { return s.(QueueStats).PushRetries }},
+	{"mempool_queue_pop_retries_total", "CAS retries on pop.", "counter", func(s any) int64 { return s.(QueueStats).PopRetries }},
+}
+
+// Exporter renders registered pool and queue stats in the Prometheus text
+// format. It implements http.Handler, so it can be mounted on gin with
+// gin.WrapH(exporter).
+type Exporter struct {
+	mu     sync.RWMutex
+	pools  map[string]PoolStatsSource
+	queues map[string]QueueStatsSource
+}
+
+func NewExporter() *Exporter {
+	return &Exporter{
+		pools:  make(map[string]PoolStatsSource),
+		queues: make(map[string]QueueStatsSource),
+	}
+}
+
+func (e *Exporter) RegisterPool(name string, src PoolStatsSource) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.pools[name] = src
+}
+
+func (e *Exporter) RegisterQueue(name string, src QueueStatsSource) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.queues[name] = src
+}
+
+func (e *Exporter) Unregister(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.pools, name)
+	delete(e.queues, name)
+}
+
+func (e *Exporter) WriteTo(w io.Writer) (int64, error) {
+	e.mu.RLock()
+	pools := make(map[string]any, len(e.pools))
+	for name, src := range e.pools {
+		pools[name] = src.Stats()
+	}
+	queues := make(map[string]any, len(e.queues))
+	for name, src := range e.queues {
+		queues[name] = src.Stats()
+	}
+	e.mu.RUnlock()
+
+	cw := &countWriter{w: bufio.NewWriter(w)}
+	writeMetrics(cw, "pool", poolMetrics, pools)
+	writeMetrics(cw, "queue", queueMetrics, queues)
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	e.WriteTo(w)
+}
+
+func writeMetrics(w *countWriter, label string, descs []metricDesc, stats map[string]any) {
+	if len(stats) == 0 {
+		return
+	}
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, d := range descs {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.name, d.help, d.name, d.kind)
+		for _, name := range names {
+			fmt.Fprintf(w, "%s{%s=%s} %d\n", d.name, label, quoteLabel(name), d.value(stats[name]))
+		}
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func quoteLabel(v string) string {
+	return `"` + labelEscaper.Replace(v) + `"`
+}
+
+type countWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
@@ -232,10 +232,26 @@ func newChPool[O any]() iMemPool[O] {
 	return new(ChMemPool[O])
 }
 
+func newStackPool[O any]() iMemPool[O] {
+	return new(StackMemPool[O])
+}
+
+func newMPMCPool[O any]() iMemPool[O] {
+	return new(MPMCMemPool[O])
+}
+
 func newRawPool[O any]() iMemPool[O] {
 	return new(RawMemPool[O])
 }
 
+func newZeroMemPool[O any](policy ZeroPolicy) func() iMemPool[O] {
+	return func() iMemPool[O] {
+		pool := new(MemPool[O])
+		pool.SetZeroPolicy(policy)
+		return pool
+	}
+}
+
 func BenchmarkMemPoolRW(b *testing.B) {
 	pt := &PoolTester[object, *object]{
 		pool:     &MemPool[object]{},
@@ -285,9 +301,11 @@ func BenchmarkMultiObj16RW(b *testing.B) {
 	pt := &MultiTester[object16, *object16]{
 		name: "obj-16B",
 		makers: map[string]func() iMemPool[object16]{
-			"casq": newMemPool[object16],
-			"chan": newChPool[object16],
-			"raw":  newRawPool[object16],
+			"casq":  newMemPool[object16],
+			"chan":  newChPool[object16],
+			"stack": newStackPool[object16],
+			"mpmc":  newMPMCPool[object16],
+			"raw":   newRawPool[object16],
 		},
 		size:  (1 << 16),
 		batch: 1 << 12,
@@ -300,9 +318,11 @@ func BenchmarkMultiObj256RW(b *testing.B) {
 	pt := &MultiTester[object256, *object256]{
 		name: "obj-256B",
 		makers: map[string]func() iMemPool[object256]{
-			"casq": newMemPool[object256],
-			"chan": newChPool[object256],
-			"raw":  newRawPool[object256],
+			"casq":  newMemPool[object256],
+			"chan":  newChPool[object256],
+			"stack": newStackPool[object256],
+			"mpmc":  newMPMCPool[object256],
+			"raw":   newRawPool[object256],
 		},
 		size:  (1 << 16),
 		batch: 1 << 12,
@@ -315,9 +335,26 @@ func BenchmarkMultiObj4096RW(b *testing.B) {
 	pt := &MultiTester[object4096, *object4096]{
 		name: "obj-4096B",
 		makers: map[string]func() iMemPool[object4096]{
-			"casq": newMemPool[object4096],
-			"chan": newChPool[object4096],
-			"raw":  newRawPool[object4096],
+			"casq":  newMemPool[object4096],
+			"chan":  newChPool[object4096],
+			"stack": newStackPool[object4096],
+			"mpmc":  newMPMCPool[object4096],
+			"raw":   newRawPool[object4096],
+		},
+		size:  (1 << 16),
+		batch: 1 << 12,
+		cp:    benchmarkCPs,
+	}
+	pt.Benchmark(b)
+}
+
+func BenchmarkMultiObj4096Zero(b *testing.B) {
+	pt := &MultiTester[object4096, *object4096]{
+		name: "obj-4096B",
+		makers: map[string]func() iMemPool[object4096]{
+			"casq":       newMemPool[object4096],
+			"casq-zfree": newZeroMemPool[object4096](ZeroOnFree),
+			"casq-znew":  newZeroMemPool[object4096](ZeroOnNew),
 		},
 		size:  (1 << 16),
 		batch: 1 << 12,
@@ -325,3 +362,72 @@ func BenchmarkMultiObj4096RW(b *testing.B) {
 	}
 	pt.Benchmark(b)
 }
+
+type iBatchPool[T any] interface {
+	iMemPool[T]
+	NewN(dst []*T) int
+	FreeN(ptrs []*T) int
+}
+
+// BatchTester allocates and frees batch objects per iteration, either with
+// NewN/FreeN or with one New/Free per object.
+type BatchTester[O any] struct {
+	name   string
+	makers map[string]func() iBatchPool[O]
+	cpus   []int
+	size   int64
+	batch  int
+}
+
+func (bt *BatchTester[O]) Benchmark(b *testing.B) {
+	for poolType, maker := range bt.makers {
+		for _, cpus := range bt.cpus {
+			for _, batched := range []bool{true, false} {
+				mode := "single"
+				if batched {
+					mode = "batch"
+				}
+				pool := maker()
+				b.Run(fmt.Sprintf("%s-%s-%s-%dC", bt.name, poolType, mode, cpus), func(b *testing.B) {
+					runtime.GOMAXPROCS(cpus)
+					pool.Init(bt.size)
+					b.RunParallel(func(pb *testing.PB) {
+						ptrs := make([]*O, bt.batch)
+						for pb.Next() {
+							if batched {
+								n := pool.NewN(ptrs)
+								pool.FreeN(ptrs[:n])
+								continue
+							}
+							n := 0
+							for ; n < len(ptrs); n++ {
+								if ptrs[n] = pool.New(); ptrs[n] == nil {
+									break
+								}
+							}
+							for _, ptr := range ptrs[:n] {
+								pool.Free(ptr)
+							}
+						}
+					})
+				})
+			}
+		}
+	}
+}
+
+func BenchmarkMultiObj256Batch(b *testing.B) {
+	bt := &BatchTester[object256]{
+		name: "obj-256B",
+		makers: map[string]func() iBatchPool[object256]{
+			"casq":  func() iBatchPool[object256] { return new(MemPool[object256]) },
+			"chan":  func() iBatchPool[object256] { return new(ChMemPool[object256]) },
+			"stack": func() iBatchPool[object256] { return new(StackMemPool[object256]) },
+			"mpmc":  func() iBatchPool[object256] { return new(MPMCMemPool[object256]) },
+		},
+		cpus:  []int{1, 2, 4},
+		size:  1 << 16,
+		batch: 32,
+	}
+	bt.Benchmark(b)
+}
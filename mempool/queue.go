@@ -0,0 +1,190 @@
+package mempool
+
+import "sync/atomic"
+
+type queueCell[T any] struct {
+	seq atomic.Int64
+	val T
+}
+
+type cacheLinePad [64]byte
+
+// Queue is a bounded lock-free MPMC queue. Each cell carries a sequence
+// number, so a slot is only written after it has been claimed and only read
+// after it has been published (Vyukov's design).
+type Queue[T any] struct {
+	_       cacheLinePad
+	enqueue atomic.Int64
+	_       cacheLinePad
+	dequeue atomic.Int64
+	_       cacheLinePad
+	cells   []queueCell[T]
+	mask    int64
+
+	pushFailed  atomic.Int64
+	popFailed   atomic.Int64
+	pushRetries atomic.Int64
+	popRetries  atomic.Int64
+}
+
+// Init sizes the queue to the next power of two >= size and empties it.
+func (q *Queue[T]) Init(size int64) {
+	capacity := int64(1)
+	for capacity < size {
+		capacity <<= 1
+	}
+	q.cells = make([]queueCell[T], capacity)
+	for i := range q.cells {
+		q.cells[i].seq.Store(int64(i))
+	}
+	q.mask = capacity - 1
+	q.enqueue.Store(0)
+	q.dequeue.Store(0)
+}
+
+func (q *Queue[T]) Cap() int64 {
+	return int64(len(q.cells))
+}
+
+func (q *Queue[T]) Len() int64 {
+	n := q.enqueue.Load() - q.dequeue.Load()
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+func (q *Queue[T]) TryPush(val T) bool {
+	for {
+		pos := q.enqueue.Load()
+		cell := &q.cells[pos&q.mask]
+		seq := cell.seq.Load()
+		switch {
+		case seq == pos:
+			if q.enqueue.CompareAndSwap(pos, pos+1) {
+				cell.val = val
+				cell.seq.Store(pos + 1)
+				return true
+			}
+			q.pushRetries.Add(1)
+		case seq < pos:
+			q.pushFailed.Add(1)
+			return false
+		}
+	}
+}
+
+func (q *Queue[T]) TryPop() (val T, ok bool) {
+	for {
+		pos := q.dequeue.Load()
+		cell := &q.cells[pos&q.mask]
+		seq := cell.seq.Load()
+		switch {
+		case seq == pos+1:
+			if q.dequeue.CompareAndSwap(pos, pos+1) {
+				val = cell.val
+				var zero T
+				cell.val = zero
+				cell.seq.Store(pos + q.mask + 1)
+				return val, true
+			}
+			q.popRetries.Add(1)
+		case seq < pos+1:
+			q.popFailed.Add(1)
+			return val, false
+		}
+	}
+}
+
+// TryPushN pushes a prefix of vals with a single reservation and returns how
+// many were pushed.
+func (q *Queue[T]) TryPushN(vals []T) int {
+	if len(vals) == 0 {
+		return 0
+	}
+	for {
+		pos := q.enqueue.Load()
+		n := int64(0)
+		for n < int64(len(vals)) && n <= q.mask && q.cells[(pos+n)&q.mask].seq.Load() == pos+n {
+			n++
+		}
+		if n == 0 {
+			if q.cells[pos&q.mask].seq.Load() < pos {
+				q.pushFailed.Add(1)
+				return 0
+			}
+			continue
+		}
+		if q.enqueue.CompareAndSwap(pos, pos+n) {
+			for i := int64(0); i < n; i++ {
+				cell := &q.cells[(pos+i)&q.mask]
+				cell.val = vals[i]
+				cell.seq.Store(pos + i + 1)
+			}
+			return int(n)
+		}
+		q.pushRetries.Add(1)
+	}
+}
+
+// TryPopN pops up to len(dst) values with a single reservation and returns
+// how many were stored in dst.
+func (q *Queue[T]) TryPopN(dst []T) int {
+	if len(dst) == 0 {
+		return 0
+	}
+	var zero T
+	for {
+		pos := q.dequeue.Load()
+		n := int64(0)
+		for n < int64(len(dst)) && n <= q.mask && q.cells[(pos+n)&q.mask].seq.Load() == pos+n+1 {
+			n++
+		}
+		if n == 0 {
+			if q.cells[pos&q.mask].seq.Load() < pos+1 {
+				q.popFailed.Add(1)
+				return 0
+			}
+			continue
+		}
+		if q.dequeue.CompareAndSwap(pos, pos+n) {
+			for i := int64(0); i < n; i++ {
+				cell := &q.cells[(pos+i)&q.mask]
+				dst[i] = cell.val
+				cell.val = zero
+				cell.seq.Store(pos + i + q.mask + 1)
+			}
+			return int(n)
+		}
+		q.popRetries.Add(1)
+	}
+}
+
+// Push, Pop, PushN and PopN are TryPush, TryPop, TryPushN and TryPopN under
+// the names a Pool free list uses, so a Queue[int64] can back a Pool.
+func (q *Queue[T]) Push(val T) bool {
+	return q.TryPush(val)
+}
+
+func (q *Queue[T]) Pop() (T, bool) {
+	return q.TryPop()
+}
+
+func (q *Queue[T]) PushN(vals []T) int {
+	return q.TryPushN(vals)
+}
+
+func (q *Queue[T]) PopN(dst []T) int {
+	return q.TryPopN(dst)
+}
+
+func (q *Queue[T]) Stats() QueueStats {
+	return QueueStats{
+		Capacity:    q.Cap(),
+		Len:         q.Len(),
+		PushFailed:  q.pushFailed.Load(),
+		PopFailed:   q.popFailed.Load(),
+		PushRetries: q.pushRetries.Load(),
+		PopRetries:  q.popRetries.Load(),
+	}
+}
@@ -12,8 +12,10 @@ type aQueueShift32 struct {
 	rw   uint64 // u64[r[u32]w[u32]]
 	size uint32
 
-	popFailed  int64
-	pushFailed int64
+	pushFailed  int64
+	popFailed   int64
+	pushRetries int64
+	popRetries  int64
 }
 
 func (a *aQueueShift32) Init(size int64) {
@@ -25,6 +27,9 @@ func (a *aQueueShift32) Init(size int64) {
 // full : (w+1) % size = r
 // _ _ _ _ _ _ _ _
 // r ----------> w
+//
+// data[w] is written before the CAS, so two pushers can overwrite each
+// other's slot. Kept for benchmarks only; use Queue[T] instead.
 func (a *aQueueShift32) Push(val int64) bool {
 
 	for {
@@ -38,8 +43,9 @@ func (a *aQueueShift32) Push(val int64) bool {
 			if atomic.CompareAndSwapUint64(&a.rw, rw, uint64(w)+(uint64(r)<<32)) {
 				return true
 			}
-			atomic.AddInt64(&a.popFailed, 1)
+			atomic.AddInt64(&a.pushRetries, 1)
 		} else {
+			atomic.AddInt64(&a.pushFailed, 1)
 			return false
 		}
 	}
@@ -62,9 +68,24 @@ func (a *aQueueShift32) Pop() (int64, bool) {
 			if atomic.CompareAndSwapUint64(&a.rw, rw, uint64(w)+(uint64(r)<<32)) {
 				return val, true
 			}
-			atomic.AddInt64(&a.pushFailed, 1)
+			atomic.AddInt64(&a.popRetries, 1)
 		} else {
+			atomic.AddInt64(&a.popFailed, 1)
 			return -1, false
 		}
 	}
 }
+
+func (a *aQueueShift32) Stats() QueueStats {
+	rw := atomic.LoadUint64(&a.rw)
+	r := uint32((rw & UpperBit) >> 32)
+	w := uint32(rw & LowerBit)
+	return QueueStats{
+		Capacity:    int64(a.size) - 1,
+		Len:         int64((w + a.size - r) % a.size),
+		PushFailed:  atomic.LoadInt64(&a.pushFailed),
+		PopFailed:   atomic.LoadInt64(&a.popFailed),
+		PushRetries: atomic.LoadInt64(&a.pushRetries),
+		PopRetries:  atomic.LoadInt64(&a.popRetries),
+	}
+}
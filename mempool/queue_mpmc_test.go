@@ -0,0 +1,137 @@
+package mempool
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestQueueOrder(t *testing.T) {
+	q := &Queue[string]{}
+	q.Init(3)
+	if q.Cap() != 4 {
+		t.Fatalf("cap %d, expected 4", q.Cap())
+	}
+	if n := q.TryPushN([]string{"a", "b", "c"}); n != 3 {
+		t.Fatalf("pushed %d", n)
+	}
+	if !q.TryPush("d") || q.TryPush("e") {
+		t.Fatal("queue should hold exactly 4")
+	}
+	dst := make([]string, 3)
+	if n := q.TryPopN(dst); n != 3 || dst[0] != "a" || dst[2] != "c" {
+		t.Fatalf("popped %d: %v", n, dst)
+	}
+	if v, ok := q.TryPop(); !ok || v != "d" {
+		t.Fatalf("pop got %q", v)
+	}
+	if _, ok := q.TryPop(); ok {
+		t.Fatal("queue should be empty")
+	}
+}
+
+// TestQueueLinearizable checks that every pushed value is popped exactly once
+// and that values from one producer are seen in push order. Run with -race.
+func TestQueueLinearizable(t *testing.T) {
+	const producers, consumers, perProducer = 4, 4, 20000
+	q := &Queue[int64]{}
+	q.Init(64)
+
+	var wg sync.WaitGroup
+	for p := int64(0); p < producers; p++ {
+		wg.Add(1)
+		go func(p int64) {
+			defer wg.Done()
+			batch := make([]int64, 0, 4)
+			for i := int64(0); i < perProducer; {
+				if i%2 == 0 {
+					if q.TryPush(p<<32 | i) {
+						i++
+					} else {
+						runtime.Gosched()
+					}
+					continue
+				}
+				batch = batch[:0]
+				for j := i; j < perProducer && len(batch) < cap(batch); j++ {
+					batch = append(batch, p<<32|j)
+				}
+				n := q.TryPushN(batch)
+				if n == 0 {
+					runtime.Gosched()
+				}
+				i += int64(n)
+			}
+		}(p)
+	}
+
+	results := make([][]int64, consumers)
+	var remaining sync.WaitGroup
+	remaining.Add(producers * perProducer)
+	done := make(chan struct{})
+	go func() {
+		remaining.Wait()
+		close(done)
+	}()
+	var cwg sync.WaitGroup
+	for c := 0; c < consumers; c++ {
+		cwg.Add(1)
+		go func(c int) {
+			defer cwg.Done()
+			buf := make([]int64, 3)
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				n := 0
+				if c%2 == 0 {
+					if v, ok := q.TryPop(); ok {
+						buf[0], n = v, 1
+					}
+				} else {
+					n = q.TryPopN(buf)
+				}
+				if n == 0 {
+					runtime.Gosched()
+				}
+				for _, v := range buf[:n] {
+					results[c] = append(results[c], v)
+					remaining.Done()
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+	cwg.Wait()
+
+	seen := make(map[int64]bool, producers*perProducer)
+	for c, values := range results {
+		last := make(map[int64]int64)
+		for _, v := range values {
+			if seen[v] {
+				t.Fatalf("value %x popped twice", v)
+			}
+			seen[v] = true
+			p, i := v>>32, v&0xFFFFFFFF
+			if prev, ok := last[p]; ok && i <= prev {
+				t.Fatalf("consumer %d saw producer %d out of order: %d after %d", c, p, i, prev)
+			}
+			last[p] = i
+		}
+	}
+	if len(seen) != producers*perProducer {
+		t.Fatalf("popped %d values, expected %d", len(seen), producers*perProducer)
+	}
+}
+
+func BenchmarkMPMCQueue(b *testing.B) {
+	qt := QueueTester{
+		queue:    &Queue[int64]{},
+		parallel: 4,
+		size:     1 << 16,
+		batch:    1 << 12,
+	}
+	qt.BenchmarkPool(b)
+}
@@ -155,8 +155,8 @@ func BenchmarkAQueue32Shift(b *testing.B) {
 	}
 
 	qt.BenchmarkPool(b)
-	b.Logf("pop failed: %d", aq.popFailed)
-	b.Logf("push failed: %d", aq.pushFailed)
+	b.Logf("pop retries: %d", aq.popRetries)
+	b.Logf("push retries: %d", aq.pushRetries)
 }
 
 func TestQueueSize(t *testing.T) {
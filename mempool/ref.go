@@ -0,0 +1,139 @@
+package mempool
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// refBox packs a generation in the upper 32 bits of state and the reference
+// count in the lower 32. The generation moves on every time the box goes back
+// to the pool, so a Ref left over from an earlier owner is caught instead of
+// touching the new owner's count.
+type refBox[T any] struct {
+	state atomic.Uint64
+	pool  *RefPool[T]
+	value T
+}
+
+const refCountMask = 1<<32 - 1
+
+// Reset is called by the inner pool on Free. The inner pool never zeroes, so
+// the generation in state survives; RefPool zeroes only the value. Under
+// ZeroOnNew the value is reset in New, after it is zeroed.
+func (b *refBox[T]) Reset() {
+	if b.pool.zero == ZeroOnFree {
+		var zero T
+		b.value = zero
+	}
+	if b.pool.zero != ZeroOnNew {
+		b.resetValue()
+	}
+}
+
+func (b *refBox[T]) resetValue() {
+	if r, ok := any(&b.value).(Resetter); ok {
+		r.Reset()
+	}
+}
+
+// RefPool hands out reference-counted objects which go back to the pool when
+// the last holder releases them.
+type RefPool[T any] struct {
+	pool MemPool[refBox[T]]
+	zero ZeroPolicy
+}
+
+func (p *RefPool[T]) SetZeroPolicy(policy ZeroPolicy) {
+	p.zero = policy
+}
+
+func (p *RefPool[T]) Init(size int64) {
+	p.pool.Init(size)
+}
+
+// New returns a Ref holding the only reference, or a nil Ref if the pool is
+// exhausted.
+func (p *RefPool[T]) New() Ref[T] {
+	box := p.pool.New()
+	if box == nil {
+		return Ref[T]{}
+	}
+	box.pool = p
+	if p.zero == ZeroOnNew {
+		var zero T
+		box.value = zero
+		box.resetValue()
+	}
+	gen := uint32(box.state.Load() >> 32)
+	box.state.Store(uint64(gen)<<32 | 1)
+	return Ref[T]{box: box, gen: gen}
+}
+
+func (p *RefPool[T]) Stats() PoolStats {
+	return p.pool.Stats()
+}
+
+type Ref[T any] struct {
+	box *refBox[T]
+	gen uint32
+}
+
+func (r Ref[T]) IsNil() bool {
+	return r.box == nil
+}
+
+func (r Ref[T]) Value() *T {
+	return &r.box.value
+}
+
+// Count returns the number of live references, or 0 once r's object has gone
+// back to the pool.
+func (r Ref[T]) Count() int64 {
+	state := r.box.state.Load()
+	if uint32(state>>32) != r.gen {
+		return 0
+	}
+	return int64(state & refCountMask)
+}
+
+// load returns the box state, panicking if r no longer holds a reference.
+func (r Ref[T]) load(op string) uint64 {
+	state := r.box.state.Load()
+	if uint32(state>>32) != r.gen || state&refCountMask == 0 {
+		panic(fmt.Sprintf("mempool: %s on released ref %p", op, r.box))
+	}
+	return state
+}
+
+// Retain adds a reference and returns r so it can be handed to another holder.
+func (r Ref[T]) Retain() Ref[T] {
+	for {
+		state := r.load("retain")
+		if r.box.state.CompareAndSwap(state, state+1) {
+			return r
+		}
+	}
+}
+
+// Release drops a reference and reports whether it was the last one, in which
+// case the object has been returned to the pool.
+func (r Ref[T]) Release() bool {
+	for {
+		state := r.load("release")
+		next := state - 1
+		last := next&refCountMask == 0
+		if last {
+			next = uint64(r.gen+1) << 32
+		}
+		if !r.box.state.CompareAndSwap(state, next) {
+			continue
+		}
+		if !last {
+			return false
+		}
+		if !r.box.pool.pool.Free(r.box) {
+			panic(fmt.Sprintf("mempool: ref %p not owned by its pool", r.box))
+		}
+		return true
+	}
+}
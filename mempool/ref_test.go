@@ -0,0 +1,110 @@
+package mempool
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestRefPool(t *testing.T) {
+	pool := &RefPool[object256]{}
+	pool.Init(1)
+	ref := pool.New()
+	if ref.IsNil() || ref.Count() != 1 {
+		t.Fatalf("unexpected ref count %d", ref.Count())
+	}
+	if !pool.New().IsNil() {
+		t.Fatal("pool should be exhausted")
+	}
+
+	const consumers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < consumers; i++ {
+		wg.Add(1)
+		go func(r Ref[object256]) {
+			defer wg.Done()
+			_ = r.Value().Data[0]
+			r.Release()
+		}(ref.Retain())
+	}
+	wg.Wait()
+	if ref.Count() != 1 || pool.Stats().InUse != 1 {
+		t.Fatalf("ref recycled early: count=%d", ref.Count())
+	}
+	if !ref.Release() {
+		t.Fatal("last release should recycle")
+	}
+	if pool.Stats().InUse != 0 || pool.New().IsNil() {
+		t.Fatal("ref not returned to pool")
+	}
+}
+
+func TestRefOverRelease(t *testing.T) {
+	pool := &RefPool[object16]{}
+	pool.Init(2)
+	ref := pool.New()
+	ref.Release()
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic on negative count")
+		}
+	}()
+	ref.Release()
+}
+
+func TestRefStaleRelease(t *testing.T) {
+	pool := &RefPool[object16]{}
+	pool.Init(1)
+	stale := pool.New()
+	stale.Release()
+	owner := pool.New()
+	if owner.box != stale.box {
+		t.Fatal("expected the box to be reused")
+	}
+	if stale.Count() != 0 || owner.Count() != 1 {
+		t.Fatalf("stale count %d, owner count %d", stale.Count(), owner.Count())
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic on stale release")
+		}
+		if owner.Count() != 1 || pool.Stats().InUse != 1 {
+			t.Fatalf("stale release touched the new owner: count=%d", owner.Count())
+		}
+	}()
+	stale.Release()
+}
+
+func TestRefRetainReleased(t *testing.T) {
+	pool := &RefPool[object16]{}
+	pool.Init(1)
+	ref := pool.New()
+	ref.Release()
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected panic on retain after release")
+			}
+		}()
+		ref.Retain()
+	}()
+	if pool.Stats().InUse != 0 || pool.New().IsNil() {
+		t.Fatal("retain on a released ref leaked the slot")
+	}
+}
+
+func TestRefPoolResetterZeroOnNew(t *testing.T) {
+	pool := &RefPool[resetRecord]{}
+	pool.SetZeroPolicy(ZeroOnNew)
+	pool.Init(1)
+	ref := pool.New()
+	if ref.Value().Price != -1 {
+		t.Fatalf("new value not reset: %+v", *ref.Value())
+	}
+	ref.Value().Price = 100
+	ref.Value().Orders = append(ref.Value().Orders, 1, 2, 3)
+	ref.Release()
+	ref = pool.New()
+	if v := ref.Value(); v.Price != -1 || len(v.Orders) != 0 {
+		t.Fatalf("value not zeroed then reset: %+v", *v)
+	}
+}
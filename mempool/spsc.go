@@ -0,0 +1,156 @@
+package mempool
+
+import "sync/atomic"
+
+// SPSCRing is a wait-free ring for exactly one producer and one consumer
+// goroutine. Each side caches the other's index and only reloads it when the
+// ring looks full or empty.
+type SPSCRing[T any] struct {
+	_         cacheLinePad
+	head      atomic.Int64 // next read, owned by the consumer
+	tailCache int64
+	_         cacheLinePad
+	tail      atomic.Int64 // next write, owned by the producer
+	headCache int64
+	_         cacheLinePad
+	buf       []T
+	mask      int64
+}
+
+// Init sizes the ring to the next power of two >= size and empties it.
+func (r *SPSCRing[T]) Init(size int64) {
+	capacity := int64(1)
+	for capacity < size {
+		capacity <<= 1
+	}
+	r.buf = make([]T, capacity)
+	r.mask = capacity - 1
+	r.head.Store(0)
+	r.tail.Store(0)
+	r.headCache = 0
+	r.tailCache = 0
+}
+
+func (r *SPSCRing[T]) Cap() int64 {
+	return int64(len(r.buf))
+}
+
+func (r *SPSCRing[T]) Len() int64 {
+	return r.tail.Load() - r.head.Load()
+}
+
+// writable returns how many slots the producer may fill from tail t.
+func (r *SPSCRing[T]) writable(t, want int64) int64 {
+	free := int64(len(r.buf)) - (t - r.headCache)
+	if free < want {
+		r.headCache = r.head.Load()
+		free = int64(len(r.buf)) - (t - r.headCache)
+	}
+	return free
+}
+
+// readable returns how many slots the consumer may read from head h.
+func (r *SPSCRing[T]) readable(h, want int64) int64 {
+	avail := r.tailCache - h
+	if avail < want {
+		r.tailCache = r.tail.Load()
+		avail = r.tailCache - h
+	}
+	return avail
+}
+
+func (r *SPSCRing[T]) Push(val T) bool {
+	t := r.tail.Load()
+	if r.writable(t, 1) < 1 {
+		return false
+	}
+	r.buf[t&r.mask] = val
+	r.tail.Store(t + 1)
+	return true
+}
+
+func (r *SPSCRing[T]) Pop() (val T, ok bool) {
+	h := r.head.Load()
+	if r.readable(h, 1) < 1 {
+		return val, false
+	}
+	slot := &r.buf[h&r.mask]
+	val = *slot
+	var zero T
+	*slot = zero
+	r.head.Store(h + 1)
+	return val, true
+}
+
+func (r *SPSCRing[T]) PushN(vals []T) int {
+	t := r.tail.Load()
+	n := r.writable(t, int64(len(vals)))
+	if n > int64(len(vals)) {
+		n = int64(len(vals))
+	}
+	for i := int64(0); i < n; i++ {
+		r.buf[(t+i)&r.mask] = vals[i]
+	}
+	r.tail.Store(t + n)
+	return int(n)
+}
+
+func (r *SPSCRing[T]) PopN(dst []T) int {
+	h := r.head.Load()
+	n := r.readable(h, int64(len(dst)))
+	if n > int64(len(dst)) {
+		n = int64(len(dst))
+	}
+	var zero T
+	for i := int64(0); i < n; i++ {
+		slot := &r.buf[(h+i)&r.mask]
+		dst[i] = *slot
+		*slot = zero
+	}
+	r.head.Store(h + n)
+	return int(n)
+}
+
+// Reserve returns up to n contiguous free slots for the producer to fill in
+// place. Nothing is visible to the consumer until Publish.
+func (r *SPSCRing[T]) Reserve(n int) []T {
+	t := r.tail.Load()
+	avail := r.writable(t, int64(n))
+	start := t & r.mask
+	if end := int64(len(r.buf)) - start; avail > end {
+		avail = end
+	}
+	if avail > int64(n) {
+		avail = int64(n)
+	}
+	return r.buf[start : start+avail]
+}
+
+// Publish makes n slots filled through Reserve visible to the consumer.
+func (r *SPSCRing[T]) Publish(n int) {
+	r.tail.Store(r.tail.Load() + int64(n))
+}
+
+// Peek returns up to n contiguous readable slots without consuming them.
+func (r *SPSCRing[T]) Peek(n int) []T {
+	h := r.head.Load()
+	avail := r.readable(h, int64(n))
+	start := h & r.mask
+	if end := int64(len(r.buf)) - start; avail > end {
+		avail = end
+	}
+	if avail > int64(n) {
+		avail = int64(n)
+	}
+	return r.buf[start : start+avail]
+}
+
+// Commit releases n slots returned by Peek back to the producer.
+func (r *SPSCRing[T]) Commit(n int) {
+	h := r.head.Load()
+	var zero T
+	for i := int64(0); i < int64(n); i++ {
+		r.buf[(h+i)&r.mask] = zero
+	}
+	r.head.Store(h + int64(n))
+}
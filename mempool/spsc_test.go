@@ -0,0 +1,140 @@
+package mempool
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestSPSCRing(t *testing.T) {
+	r := &SPSCRing[int]{}
+	r.Init(4)
+	if n := r.PushN([]int{1, 2, 3, 4, 5}); n != 4 {
+		t.Fatalf("pushed %d, expected 4", n)
+	}
+	if r.Push(6) {
+		t.Fatal("ring should be full")
+	}
+	if v, ok := r.Pop(); !ok || v != 1 {
+		t.Fatalf("pop got %d", v)
+	}
+	if p := r.Peek(8); len(p) != 3 || p[0] != 2 {
+		t.Fatalf("peek got %v", p)
+	}
+	r.Commit(2)
+
+	// tail sits at slot 0 after wrapping, head at slot 3
+	w := r.Reserve(8)
+	if len(w) != 3 {
+		t.Fatalf("reserved %d, expected 3", len(w))
+	}
+	w[0], w[1], w[2] = 7, 8, 9
+	r.Publish(3)
+	dst := make([]int, 8)
+	if n := r.PopN(dst); n != 4 || dst[0] != 4 || dst[3] != 9 {
+		t.Fatalf("popped %v", dst[:n])
+	}
+}
+
+func TestSPSCRingConcurrent(t *testing.T) {
+	const total = 100000
+	r := &SPSCRing[int64]{}
+	r.Init(64)
+	go func() {
+		batch := make([]int64, 8)
+		for i := int64(0); i < total; {
+			for j := range batch {
+				batch[j] = i + int64(j)
+			}
+			if rest := total - i; rest < int64(len(batch)) {
+				batch = batch[:rest]
+			}
+			n := r.PushN(batch)
+			if n == 0 {
+				runtime.Gosched()
+			}
+			i += int64(n)
+		}
+	}()
+	var next int64
+	for next < total {
+		p := r.Peek(16)
+		if len(p) == 0 {
+			runtime.Gosched()
+			continue
+		}
+		for _, v := range p {
+			if v != next {
+				t.Fatalf("got %d, expected %d", v, next)
+			}
+			next++
+		}
+		r.Commit(len(p))
+	}
+}
+
+// benchmarkSPSC moves b.N values from one producer goroutine to one consumer.
+func benchmarkSPSC(b *testing.B, q iQueue) {
+	runtime.GOMAXPROCS(2)
+	q.Init(1 << 12)
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < b.N; {
+			if _, ok := q.Pop(); ok {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+		close(done)
+	}()
+	for i := 0; i < b.N; {
+		if q.Push(int64(i)) {
+			i++
+		} else {
+			runtime.Gosched()
+		}
+	}
+	<-done
+}
+
+func BenchmarkSPSCRing(b *testing.B) {
+	benchmarkSPSC(b, &SPSCRing[int64]{})
+}
+
+func BenchmarkSPSCCasQueue(b *testing.B) {
+	benchmarkSPSC(b, &casQueue{})
+}
+
+func BenchmarkSPSCChQueue(b *testing.B) {
+	benchmarkSPSC(b, &chQueue{})
+}
+
+func BenchmarkSPSCRingBatch(b *testing.B) {
+	runtime.GOMAXPROCS(2)
+	r := &SPSCRing[int64]{}
+	r.Init(1 << 12)
+	done := make(chan struct{})
+	go func() {
+		dst := make([]int64, 64)
+		for i := 0; i < b.N; {
+			n := r.PopN(dst)
+			if n == 0 {
+				runtime.Gosched()
+			}
+			i += n
+		}
+		close(done)
+	}()
+	batch := make([]int64, 64)
+	for i := 0; i < b.N; {
+		if rest := b.N - i; rest < len(batch) {
+			batch = batch[:rest]
+		}
+		n := r.PushN(batch)
+		if n == 0 {
+			runtime.Gosched()
+		}
+		i += n
+	}
+	<-done
+}
@@ -0,0 +1,120 @@
+package mempool
+
+import "sync/atomic"
+
+// treiberStack is a lock-free LIFO of slot indexes. head packs a version tag
+// in the upper 32 bits and index+1 in the lower 32 bits (0 means empty), so a
+// pop racing with a pop/push of the same index fails its CAS instead of
+// linking a stale next (ABA).
+type treiberStack struct {
+	head uint64
+	next []int64
+
+	pushFailed  atomic.Int64
+	popFailed   atomic.Int64
+	pushRetries atomic.Int64
+	popRetries  atomic.Int64
+}
+
+func (s *treiberStack) Init(size int64) {
+	s.next = make([]int64, size)
+	atomic.StoreUint64(&s.head, 0)
+}
+
+func (s *treiberStack) Push(idx int64) bool {
+	if idx < 0 || idx >= int64(len(s.next)) {
+		s.pushFailed.Add(1)
+		return false
+	}
+	for {
+		head := atomic.LoadUint64(&s.head)
+		atomic.StoreInt64(&s.next[idx], int64(head&LowerBit))
+		tag := (head & UpperBit) + (1 << 32)
+		if atomic.CompareAndSwapUint64(&s.head, head, tag|uint64(idx+1)) {
+			return true
+		}
+		s.pushRetries.Add(1)
+	}
+}
+
+func (s *treiberStack) Pop() (int64, bool) {
+	for {
+		head := atomic.LoadUint64(&s.head)
+		top := int64(head & LowerBit)
+		if top == 0 {
+			s.popFailed.Add(1)
+			return -1, false
+		}
+		next := atomic.LoadInt64(&s.next[top-1])
+		tag := (head & UpperBit) + (1 << 32)
+		if atomic.CompareAndSwapUint64(&s.head, head, tag|uint64(next)) {
+			return top - 1, true
+		}
+		s.popRetries.Add(1)
+	}
+}
+
+// PushN links idxs into a chain and pushes it with one CAS.
+func (s *treiberStack) PushN(idxs []int64) int {
+	if len(idxs) == 0 {
+		return 0
+	}
+	for _, idx := range idxs {
+		if idx < 0 || idx >= int64(len(s.next)) {
+			s.pushFailed.Add(1)
+			return 0
+		}
+	}
+	for i := 0; i < len(idxs)-1; i++ {
+		atomic.StoreInt64(&s.next[idxs[i]], idxs[i+1]+1)
+	}
+	last := idxs[len(idxs)-1]
+	for {
+		head := atomic.LoadUint64(&s.head)
+		atomic.StoreInt64(&s.next[last], int64(head&LowerBit))
+		tag := (head & UpperBit) + (1 << 32)
+		if atomic.CompareAndSwapUint64(&s.head, head, tag|uint64(idxs[0]+1)) {
+			return len(idxs)
+		}
+		s.pushRetries.Add(1)
+	}
+}
+
+// PopN unlinks up to len(dst) indexes from the top with one CAS. The chain
+// below head cannot change without bumping the tag, so it is safe to walk.
+func (s *treiberStack) PopN(dst []int64) int {
+	for {
+		head := atomic.LoadUint64(&s.head)
+		top := int64(head & LowerBit)
+		n := 0
+		for top != 0 && n < len(dst) {
+			dst[n] = top - 1
+			top = atomic.LoadInt64(&s.next[top-1])
+			n++
+		}
+		if n == 0 {
+			s.popFailed.Add(1)
+			return 0
+		}
+		tag := (head & UpperBit) + (1 << 32)
+		if atomic.CompareAndSwapUint64(&s.head, head, tag|uint64(top)) {
+			return n
+		}
+		s.popRetries.Add(1)
+	}
+}
+
+func (s *treiberStack) Stats() QueueStats {
+	var n int64
+	for top := int64(atomic.LoadUint64(&s.head) & LowerBit); top != 0 && n < int64(len(s.next)); n++ {
+		top = atomic.LoadInt64(&s.next[top-1])
+	}
+	return QueueStats{
+		Capacity:    int64(len(s.next)),
+		Len:         n,
+		PushFailed:  s.pushFailed.Load(),
+		PopFailed:   s.popFailed.Load(),
+		PushRetries: s.pushRetries.Load(),
+		PopRetries:  s.popRetries.Load(),
+	}
+}
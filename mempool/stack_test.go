@@ -0,0 +1,74 @@
+package mempool
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestStackMemPoolLIFO(t *testing.T) {
+	pool := &StackMemPool[object16]{}
+	pool.Init(4)
+	a := pool.New()
+	b := pool.New()
+	pool.Free(a)
+	pool.Free(b)
+	if pool.New() != b || pool.New() != a {
+		t.Fatal("stack pool should reuse the last freed slot first")
+	}
+	if stats := pool.Stats(); stats.InUse != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestTreiberStackConcurrent(t *testing.T) {
+	const size, workers, rounds = 64, 4, 20000
+	s := &treiberStack{}
+	s.Init(size)
+	for i := int64(0); i < size; i++ {
+		s.Push(i)
+	}
+	var owner [size]int32
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := int32(1); w <= workers; w++ {
+		wg.Add(1)
+		go func(w int32) {
+			defer wg.Done()
+			held := make([]int64, 0, 8)
+			for i := 0; i < rounds; i++ {
+				if idx, ok := s.Pop(); ok {
+					mu.Lock()
+					if owner[idx] != 0 {
+						t.Errorf("slot %d popped by %d while held by %d", idx, w, owner[idx])
+					}
+					owner[idx] = w
+					mu.Unlock()
+					held = append(held, idx)
+				}
+				if len(held) == cap(held) || i%2 == 0 {
+					mu.Lock()
+					for _, idx := range held {
+						owner[idx] = 0
+					}
+					mu.Unlock()
+					for _, idx := range held {
+						s.Push(idx)
+					}
+					held = held[:0]
+				}
+			}
+			mu.Lock()
+			for _, idx := range held {
+				owner[idx] = 0
+			}
+			mu.Unlock()
+			for _, idx := range held {
+				s.Push(idx)
+			}
+		}(w)
+	}
+	wg.Wait()
+	if n := s.Stats().Len; n != size {
+		t.Fatalf("stack holds %d, expected %d", n, size)
+	}
+}
@@ -0,0 +1,53 @@
+package mempool
+
+import "sync/atomic"
+
+type PoolStats struct {
+	Capacity    int64
+	InUse       int64
+	HighWater   int64
+	AllocFailed int64
+	FreeFailed  int64
+	CasRetries  int64
+}
+
+type QueueStats struct {
+	Capacity    int64
+	Len         int64
+	PushFailed  int64
+	PopFailed   int64
+	PushRetries int64
+	PopRetries  int64
+}
+
+type poolCounters struct {
+	inUse       atomic.Int64
+	highWater   atomic.Int64
+	allocFailed atomic.Int64
+	freeFailed  atomic.Int64
+}
+
+func (c *poolCounters) alloc() {
+	n := c.inUse.Add(1)
+	for {
+		hw := c.highWater.Load()
+		if n <= hw || c.highWater.CompareAndSwap(hw, n) {
+			return
+		}
+	}
+}
+
+func (c *poolCounters) free() {
+	c.inUse.Add(-1)
+}
+
+func (c *poolCounters) snapshot(capacity int64, qs QueueStats) PoolStats {
+	return PoolStats{
+		Capacity:    capacity,
+		InUse:       c.inUse.Load(),
+		HighWater:   c.highWater.Load(),
+		AllocFailed: c.allocFailed.Load(),
+		FreeFailed:  c.freeFailed.Load(),
+		CasRetries:  qs.PushRetries + qs.PopRetries,
+	}
+}
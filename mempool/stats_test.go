@@ -0,0 +1,84 @@
+package mempool
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMemPoolStats(t *testing.T) {
+	pool := &MemPool[object16]{}
+	pool.Init(4)
+	ptrs := make([]*object16, 0, 4)
+	for i := 0; i < 5; i++ {
+		if ptr := pool.New(); ptr != nil {
+			ptrs = append(ptrs, ptr)
+		}
+	}
+	pool.Free(ptrs[0])
+	pool.Free(ptrs[0])
+
+	stats := pool.Stats()
+	if stats.Capacity != 4 || stats.InUse != 3 || stats.HighWater != 4 {
+		t.Fatalf("unexpected usage: %+v", stats)
+	}
+	if stats.AllocFailed != 1 || stats.FreeFailed != 1 {
+		t.Fatalf("unexpected failures: %+v", stats)
+	}
+}
+
+func TestChMemPoolStats(t *testing.T) {
+	pool := &ChMemPool[object16]{}
+	pool.Init(2)
+	a, b := pool.New(), pool.New()
+	if pool.New() != nil {
+		t.Fatal("pool should be exhausted")
+	}
+	pool.Free(a)
+	pool.Free(b)
+
+	stats := pool.Stats()
+	if stats.InUse != 0 || stats.HighWater != 2 || stats.AllocFailed != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestQueueStats(t *testing.T) {
+	q := &aQueueShift32{}
+	q.Init(2)
+	q.Push(1)
+	q.Push(2)
+	q.Push(3)
+	q.Pop()
+	stats := q.Stats()
+	if stats.Capacity != 2 || stats.Len != 1 || stats.PushFailed != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestExporter(t *testing.T) {
+	pool := &MemPool[object16]{}
+	pool.Init(8)
+	pool.New()
+	q := &casQueue{}
+	q.Init(4)
+
+	e := NewExporter()
+	e.RegisterPool("depth", pool)
+	e.RegisterQueue(`free"list`, q)
+	var buf bytes.Buffer
+	if _, err := e.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, line := range []string{
+		"# TYPE mempool_in_use gauge",
+		`mempool_capacity{pool="depth"} 8`,
+		`mempool_in_use{pool="depth"} 1`,
+		`mempool_queue_capacity{queue="free\"list"} 4`,
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("missing %q in:\n%s", line, out)
+		}
+	}
+}
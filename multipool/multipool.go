@@ -0,0 +1,119 @@
+package multipool
+
+import (
+	"fmt"
+	"hf-utils/mempool"
+	"reflect"
+	"sort"
+	"unsafe"
+)
+
+const slabAlign = 64
+
+type typedPool struct {
+	typ   reflect.Type
+	start uintptr
+	end   uintptr
+	pool  any // *mempool.MemPool[T]
+	stats func() mempool.PoolStats
+}
+
+// MultiPool carves one fixed pool per registered record type out of a single
+// memory block, so depth, trade and order records share one budget. Types
+// must be pointer-free, since the block is a plain byte slice to the GC.
+type MultiPool struct {
+	block  []byte
+	used   uintptr
+	byType map[reflect.Type]*typedPool
+	ranges []*typedPool // sorted by start
+}
+
+func NewMultiPool(budget int) *MultiPool {
+	return &MultiPool{
+		block:  make([]byte, budget+slabAlign),
+		byType: make(map[reflect.Type]*typedPool),
+	}
+}
+
+// Register reserves slots records of T from the budget. All types must be
+// registered before the MultiPool is shared.
+func Register[T any](mp *MultiPool, slots int64) error {
+	var t T
+	typ := reflect.TypeOf(t)
+	if mempool.HasPointers(typ) {
+		return fmt.Errorf("multipool: type %s contains pointers", typ)
+	}
+	if _, ok := mp.byType[typ]; ok {
+		return fmt.Errorf("multipool: type %s already registered", typ)
+	}
+	base := uintptr(unsafe.Pointer(&mp.block[0]))
+	offset := (base+mp.used+slabAlign-1)/slabAlign*slabAlign - base
+	size := uintptr(slots) * typ.Size()
+	if slots <= 0 || size == 0 || offset+size > uintptr(len(mp.block)) {
+		return fmt.Errorf(
+			"multipool: %d x %s needs %d bytes, %d left",
+			slots, typ, size, uintptr(len(mp.block))-mp.used,
+		)
+	}
+	slab := unsafe.Slice((*T)(unsafe.Pointer(&mp.block[offset])), slots)
+	pool := &mempool.MemPool[T]{}
+	pool.InitSlab(slab)
+	mp.used = offset + size
+
+	tp := &typedPool{
+		typ:   typ,
+		start: base + offset,
+		end:   base + offset + size,
+		pool:  pool,
+		stats: pool.Stats,
+	}
+	mp.byType[typ] = tp
+	mp.ranges = append(mp.ranges, tp)
+	sort.Slice(mp.ranges, func(i, j int) bool { return mp.ranges[i].start < mp.ranges[j].start })
+	return nil
+}
+
+// New allocates a T from its registered pool. It returns nil if T was not
+// registered or its pool is exhausted.
+func New[T any](mp *MultiPool) *T {
+	var t T
+	tp, ok := mp.byType[reflect.TypeOf(t)]
+	if !ok {
+		return nil
+	}
+	return tp.pool.(*mempool.MemPool[T]).New()
+}
+
+// Free returns ptr to the pool whose address range contains it.
+func Free[T any](mp *MultiPool, ptr *T) bool {
+	tp := mp.owner(uintptr(unsafe.Pointer(ptr)))
+	if tp == nil {
+		return false
+	}
+	pool, ok := tp.pool.(*mempool.MemPool[T])
+	if !ok {
+		return false
+	}
+	return pool.Free(ptr)
+}
+
+func (mp *MultiPool) owner(addr uintptr) *typedPool {
+	i := sort.Search(len(mp.ranges), func(i int) bool { return mp.ranges[i].end > addr })
+	if i < len(mp.ranges) && mp.ranges[i].start <= addr {
+		return mp.ranges[i]
+	}
+	return nil
+}
+
+// Used returns the number of bytes of the budget reserved so far.
+func (mp *MultiPool) Used() int {
+	return int(mp.used)
+}
+
+func (mp *MultiPool) Stats() map[string]mempool.PoolStats {
+	stats := make(map[string]mempool.PoolStats, len(mp.byType))
+	for typ, tp := range mp.byType {
+		stats[typ.String()] = tp.stats()
+	}
+	return stats
+}
@@ -0,0 +1,67 @@
+package multipool
+
+import (
+	"hf-utils/sharedmemory"
+	"testing"
+)
+
+type trade struct {
+	Price     int64
+	Volume    int64
+	Timestamp int64
+}
+
+type order struct {
+	ID    int64
+	Price int64
+	Side  int8
+}
+
+func TestMultiPool(t *testing.T) {
+	mp := NewMultiPool(1 << 20)
+	if err := Register[sharedmemory.FullDepth](mp, 64); err != nil {
+		t.Fatal(err)
+	}
+	if err := Register[trade](mp, 1024); err != nil {
+		t.Fatal(err)
+	}
+	if err := Register[order](mp, 1024); err != nil {
+		t.Fatal(err)
+	}
+	if err := Register[trade](mp, 1); err == nil {
+		t.Fatal("duplicate registration should fail")
+	}
+	if err := Register[struct{ S string }](mp, 1); err == nil {
+		t.Fatal("pointer type should be rejected")
+	}
+	if err := Register[[1024]int64](mp, 1024); err == nil {
+		t.Fatal("registration over budget should fail")
+	}
+
+	depth := New[sharedmemory.FullDepth](mp)
+	tr := New[trade](mp)
+	o := New[order](mp)
+	if depth == nil || tr == nil || o == nil {
+		t.Fatal("alloc failed")
+	}
+	depth.Asks[99].Volume = 1
+	tr.Volume = 2
+	o.Side = -1
+	if New[int32](mp) != nil {
+		t.Fatal("unregistered type should not allocate")
+	}
+
+	if Free(mp, (*order)(nil)) || Free(mp, &trade{}) {
+		t.Fatal("foreign pointers should be rejected")
+	}
+	if !Free(mp, depth) || !Free(mp, tr) || !Free(mp, o) {
+		t.Fatal("free failed")
+	}
+	if Free(mp, tr) {
+		t.Fatal("double free should fail")
+	}
+	stats := mp.Stats()
+	if s := stats["multipool.trade"]; s.Capacity != 1024 || s.HighWater != 1 || s.InUse != 0 {
+		t.Fatalf("unexpected trade stats: %+v", s)
+	}
+}
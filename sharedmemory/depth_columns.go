@@ -0,0 +1,57 @@
+package sharedmemory
+
+import (
+	"fmt"
+	"hf-utils/vector"
+)
+
+// DepthColumnDefs describes FullDepth as columns: timestamp, price, then
+// ask_price_i, ask_volume_i, bid_price_i and bid_volume_i for the first
+// levels levels of each side.
+func DepthColumnDefs(levels int) []vector.ColumnDef[FullDepth] {
+	if levels > len(FullDepth{}.Asks) {
+		levels = len(FullDepth{}.Asks)
+	}
+	defs := []vector.ColumnDef[FullDepth]{
+		{
+			Name: "timestamp",
+			Get:  func(d *FullDepth) int64 { return d.Timestamp },
+			Set:  func(d *FullDepth, v int64) { d.Timestamp = v },
+		},
+		{
+			Name: "price",
+			Get:  func(d *FullDepth) int64 { return d.Price },
+			Set:  func(d *FullDepth, v int64) { d.Price = v },
+		},
+	}
+	for i := 0; i < levels; i++ {
+		i := i
+		defs = append(defs,
+			vector.ColumnDef[FullDepth]{
+				Name: fmt.Sprintf("ask_price_%d", i),
+				Get:  func(d *FullDepth) int64 { return d.Asks[i].Price },
+				Set:  func(d *FullDepth, v int64) { d.Asks[i].Price = v },
+			},
+			vector.ColumnDef[FullDepth]{
+				Name: fmt.Sprintf("ask_volume_%d", i),
+				Get:  func(d *FullDepth) int64 { return d.Asks[i].Volume },
+				Set:  func(d *FullDepth, v int64) { d.Asks[i].Volume = v },
+			},
+			vector.ColumnDef[FullDepth]{
+				Name: fmt.Sprintf("bid_price_%d", i),
+				Get:  func(d *FullDepth) int64 { return d.Bids[i].Price },
+				Set:  func(d *FullDepth, v int64) { d.Bids[i].Price = v },
+			},
+			vector.ColumnDef[FullDepth]{
+				Name: fmt.Sprintf("bid_volume_%d", i),
+				Get:  func(d *FullDepth) int64 { return d.Bids[i].Volume },
+				Set:  func(d *FullDepth, v int64) { d.Bids[i].Volume = v },
+			},
+		)
+	}
+	return defs
+}
+
+func NewDepthColumns(levels int) *vector.Columnar[FullDepth] {
+	return vector.NewColumnar(DepthColumnDefs(levels)...)
+}
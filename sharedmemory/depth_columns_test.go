@@ -0,0 +1,75 @@
+package sharedmemory
+
+import (
+	"hf-utils/vector"
+	"testing"
+)
+
+func TestDepthColumns(t *testing.T) {
+	dg := DepthGenerator{
+		Price:  10000,
+		Range:  11,
+		Offset: 5,
+	}
+	depths := make([]FullDepth, 64)
+	for i := range depths {
+		dg.Next(&depths[i])
+	}
+	cols := NewDepthColumns(100)
+	cols.AppendRows(depths)
+	if len(cols.Names()) != 2+4*100 {
+		t.Fatalf("columns: %d", len(cols.Names()))
+	}
+
+	rows := cols.Rows()
+	for i := range depths {
+		if rows[i] != depths[i] {
+			t.Fatalf("row %d did not round trip", i)
+		}
+	}
+
+	price, _ := cols.Column("price")
+	hi, _ := vector.Max(price)
+	bid, _ := cols.Column("bid_price_0")
+	if hi != maxPrice(depths) || bid[3] != depths[3].Bids[0].Price {
+		t.Fatalf("max price %d, bid_price_0[3] %d", hi, bid[3])
+	}
+	t.Logf("price mean: %f", vector.Mean(price))
+}
+
+func maxPrice(depths []FullDepth) int64 {
+	m := depths[0].Price
+	for i := range depths {
+		if depths[i].Price > m {
+			m = depths[i].Price
+		}
+	}
+	return m
+}
+
+func BenchmarkMaxPriceRows(b *testing.B) {
+	depths := make([]FullDepth, 1<<12)
+	dg := DepthGenerator{Price: 10000, Range: 11, Offset: 5}
+	for i := range depths {
+		dg.Next(&depths[i])
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		maxPrice(depths)
+	}
+}
+
+func BenchmarkMaxPriceColumn(b *testing.B) {
+	depths := make([]FullDepth, 1<<12)
+	dg := DepthGenerator{Price: 10000, Range: 11, Offset: 5}
+	for i := range depths {
+		dg.Next(&depths[i])
+	}
+	cols := NewDepthColumns(10)
+	cols.AppendRows(depths)
+	price, _ := cols.Column("price")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vector.Max(price)
+	}
+}
@@ -0,0 +1,13 @@
+// Package sharedmemory lays out tables, pools, vectors and rings in shared
+// memory objects so several processes can work on the same data.
+//
+// Element types must be pointer free. A Create function formats an object
+// that is already truncated to the size its matching Size function reports;
+// an Attach function maps it in another process after checking the header
+// against T. Close unmaps and closes the object in the calling process only,
+// and the segment lives on until it is destroyed.
+//
+// SeqlockTable and ShmRing copy records one 8-byte word at a time with atomic
+// loads and stores, so readers never race with the writer in the Go memory
+// model. Their element types must be a multiple of 8 bytes.
+package sharedmemory
@@ -0,0 +1,46 @@
+package sharedmemory
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+)
+
+// readerEnv carries the work count to reader processes started by
+// forkReaders.
+const readerEnv = "SHM_TEST_READER"
+
+// forkReaders re-runs the test binary n times, running only the named test
+// with readerEnv set to count, and returns a channel that yields each
+// child's exit error.
+func forkReaders(n int, test string, count int64) <-chan error {
+	done := make(chan error, n)
+	for i := 0; i < n; i++ {
+		cmd := exec.Command(os.Args[0], "-test.run=^"+test+"$", "-test.count=1")
+		cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d", readerEnv, count))
+		go func() {
+			out, err := cmd.CombinedOutput()
+			if err != nil {
+				err = fmt.Errorf("%w\n%s", err, out)
+			}
+			done <- err
+		}()
+	}
+	return done
+}
+
+// readerCount returns the count passed by forkReaders, skipping t when it
+// is run directly rather than as a child.
+func readerCount(t *testing.T) int64 {
+	v := os.Getenv(readerEnv)
+	if v == "" {
+		t.Skip("only runs as a child started by forkReaders")
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
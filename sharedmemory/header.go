@@ -0,0 +1,57 @@
+package sharedmemory
+
+import (
+	"fmt"
+	"unsafe"
+
+	"bitbucket.org/avd/go-ipc/mmf"
+	"bitbucket.org/avd/go-ipc/shm"
+)
+
+// shmMapping is a persistent read-write or read-only mapping of a segment.
+type shmMapping struct {
+	smo    shm.SharedMemoryObject
+	region *mmf.MemoryRegion
+}
+
+// Close unmaps the segment and closes the object in this process only.
+func (m shmMapping) Close() error {
+	if err := m.region.Close(); err != nil {
+		return err
+	}
+	return m.smo.Close()
+}
+
+// shmHeader starts the header of every segment layout in this package, so
+// attaching can check any of them the same way.
+type shmHeader struct {
+	magic    uint64
+	elemSize uint64
+	capacity uint64
+}
+
+// readShmHeader copies the header H from the start of obj and checks that it
+// has the given magic and was formatted for elements the size of T. H must
+// begin with a shmHeader.
+func readShmHeader[H any, T any](obj shm.SharedMemoryObject, magic uint64, kind string) (H, error) {
+	var h H
+	size := int(unsafe.Sizeof(h))
+	if obj.Size() < int64(size) {
+		return h, fmt.Errorf("shm object too small for %s header", kind)
+	}
+	region, err := mmf.NewMemoryRegion(obj, mmf.MEM_READ_ONLY, 0, size)
+	if err != nil {
+		return h, err
+	}
+	h = *(*H)(unsafe.Pointer(&region.Data()[0]))
+	region.Close()
+	common := (*shmHeader)(unsafe.Pointer(&h))
+	if common.magic != magic {
+		return h, fmt.Errorf("shm object is not an initialized %s", kind)
+	}
+	var t T
+	if common.elemSize != uint64(unsafe.Sizeof(t)) {
+		return h, fmt.Errorf("shm %s element size %d, expected %d", kind, common.elemSize, unsafe.Sizeof(t))
+	}
+	return h, nil
+}
@@ -0,0 +1,219 @@
+package sharedmemory
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync/atomic"
+	"unsafe"
+
+	"bitbucket.org/avd/go-ipc/mmf"
+	"bitbucket.org/avd/go-ipc/shm"
+
+	"hf-utils/mempool"
+)
+
+const shmPoolMagic uint64 = 0x4c504d4853464800 // "\x00HFSHMPL"
+
+const cacheLine = 64
+
+// shmPoolHeader sits at offset 0 of the segment. The enqueue and dequeue
+// positions get their own cache lines since every process hammers them.
+type shmPoolHeader struct {
+	shmHeader
+	_       [cacheLine - 24]byte
+	enqueue int64
+	_       [cacheLine - 8]byte
+	dequeue int64
+	_       [cacheLine - 8]byte
+}
+
+type shmPoolCell struct {
+	seq int64
+	val int64
+}
+
+type shmPoolLayout struct {
+	cells int64
+	tags  int64
+	slab  int64
+	size  int64
+}
+
+func alignUp(n, align int64) int64 {
+	return (n + align - 1) / align * align
+}
+
+func newShmPoolLayout(elemSize, capacity int64) shmPoolLayout {
+	var l shmPoolLayout
+	l.cells = int64(unsafe.Sizeof(shmPoolHeader{}))
+	l.tags = l.cells + capacity*int64(unsafe.Sizeof(shmPoolCell{}))
+	l.slab = alignUp(l.tags+capacity*4, cacheLine)
+	l.size = l.slab + capacity*elemSize
+	return l
+}
+
+// ShmPoolSize returns the number of bytes a segment needs to hold a pool of
+// capacity elements of T.
+func ShmPoolSize[T any](capacity int64) int64 {
+	var t T
+	return newShmPoolLayout(int64(unsafe.Sizeof(t)), capacity).size
+}
+
+// ShmPool is a fixed-size object pool whose slab, tags and free queue all live
+// in a shared memory object, so several processes can allocate from it.
+// Objects are addressed by slot index, which is valid in every process.
+type ShmPool[T any] struct {
+	shmMapping
+	header *shmPoolHeader
+	cells  []shmPoolCell
+	tags   []uint32
+	slab   []T
+}
+
+func checkPointerFree(t reflect.Type) error {
+	if mempool.HasPointers(t) {
+		return fmt.Errorf("type %s contains pointers and cannot be shared", t)
+	}
+	return nil
+}
+
+func mapShmPool[T any](obj shm.SharedMemoryObject, capacity int64) (*ShmPool[T], error) {
+	var t T
+	if err := checkPointerFree(reflect.TypeOf(t)); err != nil {
+		return nil, err
+	}
+	layout := newShmPoolLayout(int64(unsafe.Sizeof(t)), capacity)
+	if obj.Size() < layout.size {
+		return nil, fmt.Errorf("shm pool needs %d bytes, object has %d", layout.size, obj.Size())
+	}
+	region, err := mmf.NewMemoryRegion(obj, mmf.MEM_READWRITE, 0, int(layout.size))
+	if err != nil {
+		return nil, err
+	}
+	data := region.Data()
+	base := unsafe.Pointer(&data[0])
+	return &ShmPool[T]{
+		shmMapping: shmMapping{obj, region},
+		header:     (*shmPoolHeader)(base),
+		cells:      unsafe.Slice((*shmPoolCell)(unsafe.Add(base, layout.cells)), capacity),
+		tags:       unsafe.Slice((*uint32)(unsafe.Add(base, layout.tags)), capacity),
+		slab:       unsafe.Slice((*T)(unsafe.Add(base, layout.slab)), capacity),
+	}, nil
+}
+
+// CreateShmPool formats obj as a pool of capacity elements.
+func CreateShmPool[T any](obj shm.SharedMemoryObject, capacity int64) (*ShmPool[T], error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("invalid shm pool capacity %d", capacity)
+	}
+	pool, err := mapShmPool[T](obj, capacity)
+	if err != nil {
+		return nil, err
+	}
+	h := pool.header
+	atomic.StoreUint64(&h.magic, 0)
+	h.elemSize = uint64(unsafe.Sizeof(pool.slab[0]))
+	h.capacity = uint64(capacity)
+	for i := range pool.cells {
+		pool.cells[i] = shmPoolCell{seq: int64(i) + 1, val: int64(i)}
+		pool.tags[i] = 0
+	}
+	h.enqueue = capacity
+	h.dequeue = 0
+	atomic.StoreUint64(&h.magic, shmPoolMagic)
+	return pool, nil
+}
+
+// AttachShmPool maps a pool created by CreateShmPool, possibly in another
+// process, after checking that it was built for the same element type size.
+func AttachShmPool[T any](obj shm.SharedMemoryObject) (*ShmPool[T], error) {
+	h, err := readShmHeader[shmPoolHeader, T](obj, shmPoolMagic, "pool")
+	if err != nil {
+		return nil, err
+	}
+	return mapShmPool[T](obj, int64(h.capacity))
+}
+
+func (p *ShmPool[T]) Cap() int64 {
+	return int64(len(p.slab))
+}
+
+// New claims a free slot and returns its index, or false if the pool is
+// exhausted.
+func (p *ShmPool[T]) New() (int64, bool) {
+	idx, ok := p.pop()
+	if !ok {
+		return -1, false
+	}
+	if !atomic.CompareAndSwapUint32(&p.tags[idx], 0, 1) {
+		panic(fmt.Sprintf("shm pool slot %d not recycled", idx))
+	}
+	return idx, true
+}
+
+// At returns the object in slot idx, mapped into this process.
+func (p *ShmPool[T]) At(idx int64) *T {
+	return &p.slab[idx]
+}
+
+// Index returns the slot index of a pointer obtained from At.
+func (p *ShmPool[T]) Index(ptr *T) (int64, bool) {
+	off := uintptr(unsafe.Pointer(ptr)) - uintptr(unsafe.Pointer(&p.slab[0]))
+	size := unsafe.Sizeof(p.slab[0])
+	if off%size != 0 || off/size >= uintptr(len(p.slab)) {
+		return -1, false
+	}
+	return int64(off / size), true
+}
+
+func (p *ShmPool[T]) Free(idx int64) bool {
+	if idx < 0 || idx >= int64(len(p.slab)) {
+		return false
+	}
+	if !atomic.CompareAndSwapUint32(&p.tags[idx], 1, 0) {
+		return false
+	}
+	for !p.push(idx) {
+		runtime.Gosched()
+	}
+	return true
+}
+
+func (p *ShmPool[T]) push(val int64) bool {
+	size := int64(len(p.cells))
+	for {
+		pos := atomic.LoadInt64(&p.header.enqueue)
+		cell := &p.cells[pos%size]
+		seq := atomic.LoadInt64(&cell.seq)
+		switch {
+		case seq == pos:
+			if atomic.CompareAndSwapInt64(&p.header.enqueue, pos, pos+1) {
+				cell.val = val
+				atomic.StoreInt64(&cell.seq, pos+1)
+				return true
+			}
+		case seq < pos:
+			return false
+		}
+	}
+}
+
+func (p *ShmPool[T]) pop() (int64, bool) {
+	size := int64(len(p.cells))
+	for {
+		pos := atomic.LoadInt64(&p.header.dequeue)
+		cell := &p.cells[pos%size]
+		seq := atomic.LoadInt64(&cell.seq)
+		switch {
+		case seq == pos+1:
+			if atomic.CompareAndSwapInt64(&p.header.dequeue, pos, pos+1) {
+				val := cell.val
+				atomic.StoreInt64(&cell.seq, pos+size)
+				return val, true
+			}
+		case seq < pos+1:
+			return -1, false
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package sharedmemory
+
+import (
+	"sync"
+	"testing"
+)
+
+const POOLNAME = "PoolTest"
+
+func TestShmPoolAttach(t *testing.T) {
+	var capacity int64 = 64
+	obj := createMemoryObject(POOLNAME, ShmPoolSize[FullDepth](capacity))
+	defer obj.Destroy()
+	writer, err := CreateShmPool[FullDepth](obj, capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+
+	idx, ok := writer.New()
+	if !ok {
+		t.Fatal("alloc failed")
+	}
+	SetRandomDepth(writer.At(idx), 10000, 1)
+
+	reader, err := AttachShmPool[FullDepth](initMemoryObject(POOLNAME))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	if reader.Cap() != capacity {
+		t.Fatalf("capacity %d, expected %d", reader.Cap(), capacity)
+	}
+	if *reader.At(idx) != *writer.At(idx) {
+		t.Fatal("reader sees different depth")
+	}
+	if i, ok := reader.Index(reader.At(idx)); !ok || i != idx {
+		t.Fatalf("index %d, expected %d", i, idx)
+	}
+	if !reader.Free(idx) || writer.Free(idx) {
+		t.Fatal("slot should be freed exactly once")
+	}
+
+	if _, err := AttachShmPool[Level](initMemoryObject(POOLNAME)); err == nil {
+		t.Fatal("attach with wrong element size should fail")
+	}
+	if _, err := CreateShmPool[struct{ P *int }](obj, capacity); err == nil {
+		t.Fatal("pointer types should be rejected")
+	}
+}
+
+func TestShmPoolConcurrent(t *testing.T) {
+	var capacity int64 = 128
+	obj := createMemoryObject(POOLNAME, ShmPoolSize[Level](capacity))
+	defer obj.Destroy()
+	first, err := CreateShmPool[Level](obj, capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	second, err := AttachShmPool[Level](initMemoryObject(POOLNAME))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+
+	var wg sync.WaitGroup
+	for w, pool := range []*ShmPool[Level]{first, second, first, second} {
+		wg.Add(1)
+		go func(w int64, pool *ShmPool[Level]) {
+			defer wg.Done()
+			held := make([]int64, 0, 16)
+			for i := 0; i < 10000; i++ {
+				if idx, ok := pool.New(); ok {
+					lv := pool.At(idx)
+					lv.Price, lv.Volume = w, int64(i)
+					held = append(held, idx)
+				}
+				if len(held) == cap(held) || i%3 == 0 {
+					for _, idx := range held {
+						if lv := pool.At(idx); lv.Price != w {
+							t.Errorf("slot %d owned by %d, expected %d", idx, lv.Price, w)
+						}
+						pool.Free(idx)
+					}
+					held = held[:0]
+				}
+			}
+			for _, idx := range held {
+				pool.Free(idx)
+			}
+		}(int64(w), pool)
+	}
+	wg.Wait()
+
+	count := int64(0)
+	for {
+		if _, ok := first.New(); !ok {
+			break
+		}
+		count++
+	}
+	if count != capacity {
+		t.Fatalf("leaked slots: %d free of %d", count, capacity)
+	}
+}
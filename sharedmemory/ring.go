@@ -0,0 +1,241 @@
+package sharedmemory
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"unsafe"
+
+	"bitbucket.org/avd/go-ipc/mmf"
+	"bitbucket.org/avd/go-ipc/shm"
+)
+
+const shmRingMagic uint64 = 0x474e495248534600 // "\x00FSHRING"
+
+// ErrLapped is returned by a ShmRingReader that fell more than a capacity
+// behind the writer; the records it missed are gone.
+var ErrLapped = errors.New("shm ring reader was lapped by the writer")
+
+// shmRingHeader sits at offset 0 of the segment, followed by one cache line
+// per reader cursor and then the records.
+type shmRingHeader struct {
+	shmHeader
+	readers uint64
+	_       [cacheLine - 32]byte
+	write   int64
+	_       [cacheLine - 8]byte
+}
+
+type shmRingCursor struct {
+	next int64
+	_    [cacheLine - 8]byte
+}
+
+type shmRingLayout struct {
+	cursors int64
+	slots   int64
+	size    int64
+}
+
+func newShmRingLayout(elemSize, capacity, readers int64) shmRingLayout {
+	var l shmRingLayout
+	l.cursors = int64(unsafe.Sizeof(shmRingHeader{}))
+	l.slots = l.cursors + readers*int64(unsafe.Sizeof(shmRingCursor{}))
+	l.size = l.slots + capacity*elemSize
+	return l
+}
+
+// ShmRingSize returns the number of bytes a segment needs to hold a ring of
+// capacity elements of T with the given number of reader cursors.
+func ShmRingSize[T any](capacity, readers int64) int64 {
+	var t T
+	return newShmRingLayout(int64(unsafe.Sizeof(t)), capacity, readers).size
+}
+
+// ShmRing is a single-writer broadcast ring buffer in shared memory. The
+// writer never waits for readers; a reader that falls a full capacity behind
+// gets ErrLapped.
+type ShmRing[T any] struct {
+	shmMapping
+	header  *shmRingHeader
+	cursors []shmRingCursor
+	slots   []uint64
+	words   int64
+	mask    int64
+}
+
+func mapShmRing[T any](obj shm.SharedMemoryObject, capacity, readers int64) (*ShmRing[T], error) {
+	var t T
+	if err := checkPointerFree(reflect.TypeOf(t)); err != nil {
+		return nil, err
+	}
+	elemSize := int64(unsafe.Sizeof(t))
+	if elemSize == 0 || elemSize%8 != 0 {
+		return nil, fmt.Errorf("shm ring needs a multiple of 8 bytes, %s has %d", reflect.TypeOf(t), elemSize)
+	}
+	layout := newShmRingLayout(elemSize, capacity, readers)
+	if obj.Size() < layout.size {
+		return nil, fmt.Errorf("shm ring needs %d bytes, object has %d", layout.size, obj.Size())
+	}
+	region, err := mmf.NewMemoryRegion(obj, mmf.MEM_READWRITE, 0, int(layout.size))
+	if err != nil {
+		return nil, err
+	}
+	data := region.Data()
+	base := unsafe.Pointer(&data[0])
+	return &ShmRing[T]{
+		shmMapping: shmMapping{obj, region},
+		header:     (*shmRingHeader)(base),
+		cursors:    unsafe.Slice((*shmRingCursor)(unsafe.Add(base, layout.cursors)), readers),
+		slots:      unsafe.Slice((*uint64)(unsafe.Add(base, layout.slots)), capacity*elemSize/8),
+		words:      elemSize / 8,
+		mask:       capacity - 1,
+	}, nil
+}
+
+// CreateShmRing formats obj as an empty ring. capacity must be a power of
+// two, at least 2; readers is the number of persistent cursors and may be
+// zero.
+func CreateShmRing[T any](obj shm.SharedMemoryObject, capacity, readers int64) (*ShmRing[T], error) {
+	if capacity < 2 || capacity&(capacity-1) != 0 {
+		return nil, fmt.Errorf("shm ring capacity %d is not a power of two above 1", capacity)
+	}
+	if readers < 0 {
+		return nil, fmt.Errorf("invalid shm ring reader count %d", readers)
+	}
+	ring, err := mapShmRing[T](obj, capacity, readers)
+	if err != nil {
+		return nil, err
+	}
+	h := ring.header
+	atomic.StoreUint64(&h.magic, 0)
+	h.elemSize = uint64(ring.words * 8)
+	h.capacity = uint64(capacity)
+	h.readers = uint64(readers)
+	atomic.StoreInt64(&h.write, 0)
+	for i := range ring.cursors {
+		atomic.StoreInt64(&ring.cursors[i].next, 0)
+	}
+	atomic.StoreUint64(&h.magic, shmRingMagic)
+	return ring, nil
+}
+
+// AttachShmRing maps a ring created by CreateShmRing, possibly in another
+// process, after checking that it was built for the same element type size.
+func AttachShmRing[T any](obj shm.SharedMemoryObject) (*ShmRing[T], error) {
+	h, err := readShmHeader[shmRingHeader, T](obj, shmRingMagic, "ring")
+	if err != nil {
+		return nil, err
+	}
+	return mapShmRing[T](obj, int64(h.capacity), int64(h.readers))
+}
+
+func (r *ShmRing[T]) Cap() int64 {
+	return r.mask + 1
+}
+
+// Readers returns the number of persistent reader cursors.
+func (r *ShmRing[T]) Readers() int {
+	return len(r.cursors)
+}
+
+// Cursor returns the sequence number the next Push will get, which is also
+// the number of records pushed so far.
+func (r *ShmRing[T]) Cursor() int64 {
+	return atomic.LoadInt64(&r.header.write)
+}
+
+// ReaderCursor returns the next sequence number of persistent reader id.
+func (r *ShmRing[T]) ReaderCursor(id int) int64 {
+	return atomic.LoadInt64(&r.cursors[id].next)
+}
+
+func (r *ShmRing[T]) slot(seq int64) []uint64 {
+	off := (seq & r.mask) * r.words
+	return r.slots[off : off+r.words]
+}
+
+// Push copies *v into the ring and returns its sequence number. Only one
+// process may push into a ring.
+func (r *ShmRing[T]) Push(v *T) int64 {
+	seq := atomic.LoadInt64(&r.header.write)
+	src := unsafe.Slice((*uint64)(unsafe.Pointer(v)), r.words)
+	slot := r.slot(seq)
+	for i := range slot {
+		atomic.StoreUint64(&slot[i], src[i])
+	}
+	atomic.StoreInt64(&r.header.write, seq+1)
+	return seq
+}
+
+// ShmRingReader tails a ShmRing from its own position.
+type ShmRingReader[T any] struct {
+	ring   *ShmRing[T]
+	next   int64
+	cursor *int64
+	lost   int64
+}
+
+// NewReader returns a reader starting at the current write position. Its
+// position is private to this process.
+func (r *ShmRing[T]) NewReader() *ShmRingReader[T] {
+	return &ShmRingReader[T]{ring: r, next: r.Cursor()}
+}
+
+// OpenReader returns a reader that resumes from, and keeps updating, the
+// persistent cursor id. Callers must not open the same id twice at once.
+func (r *ShmRing[T]) OpenReader(id int) (*ShmRingReader[T], error) {
+	if id < 0 || id >= len(r.cursors) {
+		return nil, fmt.Errorf("shm ring has %d reader cursors, got id %d", len(r.cursors), id)
+	}
+	cursor := &r.cursors[id].next
+	return &ShmRingReader[T]{ring: r, next: atomic.LoadInt64(cursor), cursor: cursor}, nil
+}
+
+// Next returns the sequence number of the next record Read will return.
+func (rd *ShmRingReader[T]) Next() int64 {
+	return rd.next
+}
+
+// Lost returns the number of records this reader skipped after being lapped.
+func (rd *ShmRingReader[T]) Lost() int64 {
+	return rd.lost
+}
+
+// Read copies the next record into *v and reports whether there was one.
+// If the writer has overwritten the record, Read skips to the oldest record
+// still in the ring and returns ErrLapped.
+func (rd *ShmRingReader[T]) Read(v *T) (bool, error) {
+	r := rd.ring
+	write := atomic.LoadInt64(&r.header.write)
+	if rd.next >= write {
+		return false, nil
+	}
+	if write-rd.next <= r.mask {
+		dst := unsafe.Slice((*uint64)(unsafe.Pointer(v)), r.words)
+		slot := r.slot(rd.next)
+		for i := range slot {
+			dst[i] = atomic.LoadUint64(&slot[i])
+		}
+		// The writer starts overwriting this slot once write reaches
+		// next+capacity, so if it is still short of that the copy is whole.
+		write = atomic.LoadInt64(&r.header.write)
+		if write-rd.next <= r.mask {
+			rd.advance(rd.next + 1)
+			return true, nil
+		}
+	}
+	// The slot of write-capacity may be mid-overwrite, so resume one later.
+	oldest := write - r.mask
+	rd.lost += oldest - rd.next
+	rd.advance(oldest)
+	return false, ErrLapped
+}
+
+func (rd *ShmRingReader[T]) advance(next int64) {
+	rd.next = next
+	if rd.cursor != nil {
+		atomic.StoreInt64(rd.cursor, next)
+	}
+}
@@ -0,0 +1,141 @@
+package sharedmemory
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+const RINGNAME = "RingTest"
+
+func TestShmRing(t *testing.T) {
+	const capacity = 8
+	obj := createMemoryObject(RINGNAME, ShmRingSize[Level](capacity, 2))
+	defer obj.Destroy()
+	writer, err := CreateShmRing[Level](obj, capacity, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+	ring, err := AttachShmRing[Level](initMemoryObject(RINGNAME))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ring.Close()
+	if ring.Cap() != capacity || ring.Readers() != 2 {
+		t.Fatalf("cap %d readers %d", ring.Cap(), ring.Readers())
+	}
+
+	tail := ring.NewReader()
+	durable, err := ring.OpenReader(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var l Level
+	if ok, err := tail.Read(&l); ok || err != nil {
+		t.Fatal("empty ring should have nothing to read")
+	}
+	for i := int64(0); i < 5; i++ {
+		writer.Push(&Level{Price: i, Volume: -i})
+	}
+	for i := int64(0); i < 3; i++ {
+		if ok, err := durable.Read(&l); !ok || err != nil || l.Price != i {
+			t.Fatalf("read %v %v %+v, expected price %d", ok, err, l, i)
+		}
+	}
+	if writer.ReaderCursor(1) != 3 {
+		t.Fatalf("cursor %d, expected 3", writer.ReaderCursor(1))
+	}
+	resumed, _ := ring.OpenReader(1)
+	if ok, _ := resumed.Read(&l); !ok || l.Price != 3 {
+		t.Fatalf("resumed reader read %+v", l)
+	}
+
+	for i := int64(5); i < 20; i++ {
+		writer.Push(&Level{Price: i, Volume: -i})
+	}
+	if _, err := tail.Read(&l); !errors.Is(err, ErrLapped) {
+		t.Fatalf("expected ErrLapped, got %v", err)
+	}
+	if tail.Next() != 20-capacity+1 || tail.Lost() != tail.Next() {
+		t.Fatalf("resumed at %d after losing %d", tail.Next(), tail.Lost())
+	}
+	for want := tail.Next(); want < 20; want++ {
+		if ok, err := tail.Read(&l); !ok || err != nil || l.Price != want {
+			t.Fatalf("read %+v, expected price %d", l, want)
+		}
+	}
+
+	if _, err := ring.OpenReader(2); err == nil {
+		t.Fatal("reader id out of range should fail")
+	}
+	if _, err := CreateShmRing[Level](obj, 6, 0); err == nil {
+		t.Fatal("capacity must be a power of two")
+	}
+	if _, err := AttachShmRing[FullDepth](initMemoryObject(RINGNAME)); err == nil {
+		t.Fatal("attach with wrong element size should fail")
+	}
+}
+
+// TestShmRingReaderProcess is the body of the child processes started by
+// TestShmRingMultiProcess; it does nothing when run directly.
+func TestShmRingReaderProcess(t *testing.T) {
+	total := readerCount(t)
+	ring, err := AttachShmRing[FullDepth](initMemoryObject(RINGNAME))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ring.Close()
+	reader := &ShmRingReader[FullDepth]{ring: ring}
+	var depth FullDepth
+	for reader.Next() < total {
+		seq := reader.Next()
+		ok, err := reader.Read(&depth)
+		if errors.Is(err, ErrLapped) {
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if err := verifyDepth(&depth); err != nil {
+			t.Fatalf("record %d: %s", seq, err)
+		}
+		if depth.Price != 10000+seq%500 {
+			t.Fatalf("record %d has price %d", seq, depth.Price)
+		}
+	}
+	t.Logf("lost %d of %d records", reader.Lost(), total)
+}
+
+func TestShmRingMultiProcess(t *testing.T) {
+	if testing.Short() {
+		t.Skip("forks child processes")
+	}
+	const children, capacity, total = 3, 4, 200000
+	obj := createMemoryObject(RINGNAME, ShmRingSize[FullDepth](capacity, 0))
+	defer obj.Destroy()
+	ring, err := CreateShmRing[FullDepth](obj, capacity, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ring.Close()
+
+	done := forkReaders(children, "TestShmRingReaderProcess", total)
+
+	var depth FullDepth
+	for n := int64(0); n < total; n++ {
+		setChecksumDepth(&depth, n)
+		ring.Push(&depth)
+	}
+	deadline := time.After(time.Minute)
+	for c := 0; c < children; c++ {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Error(err)
+			}
+		case <-deadline:
+			t.Fatal("readers did not finish")
+		}
+	}
+}
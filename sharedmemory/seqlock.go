@@ -0,0 +1,171 @@
+package sharedmemory
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync/atomic"
+	"unsafe"
+
+	"bitbucket.org/avd/go-ipc/mmf"
+	"bitbucket.org/avd/go-ipc/shm"
+)
+
+const seqlockMagic uint64 = 0x4b434c5148534600 // "\x00FSHQLCK"
+
+type seqlockHeader struct {
+	shmHeader
+	_ [cacheLine - 24]byte
+}
+
+func seqlockStride(elemSize int64) int64 {
+	return alignUp(8+elemSize, cacheLine)
+}
+
+// SeqlockTableSize returns the number of bytes a segment needs to hold a
+// table of capacity slots of T.
+func SeqlockTableSize[T any](capacity int64) int64 {
+	var t T
+	return int64(unsafe.Sizeof(seqlockHeader{})) + capacity*seqlockStride(int64(unsafe.Sizeof(t)))
+}
+
+// SeqlockTable is a table of T in shared memory where every slot is guarded
+// by a sequence counter. A single writer makes the counter odd, copies the
+// record and makes it even again; readers retry until they copy a record
+// under an unchanged even counter, so they never see a torn write.
+type SeqlockTable[T any] struct {
+	shmMapping
+	header *seqlockHeader
+	slots  unsafe.Pointer
+	stride uintptr
+	words  int
+	cap    int
+}
+
+func mapSeqlockTable[T any](obj shm.SharedMemoryObject, capacity int64) (*SeqlockTable[T], error) {
+	var t T
+	if err := checkPointerFree(reflect.TypeOf(t)); err != nil {
+		return nil, err
+	}
+	elemSize := int64(unsafe.Sizeof(t))
+	if elemSize == 0 || elemSize%8 != 0 {
+		return nil, fmt.Errorf("seqlock table needs a multiple of 8 bytes, %s has %d", reflect.TypeOf(t), elemSize)
+	}
+	size := SeqlockTableSize[T](capacity)
+	if obj.Size() < size {
+		return nil, fmt.Errorf("seqlock table needs %d bytes, object has %d", size, obj.Size())
+	}
+	region, err := mmf.NewMemoryRegion(obj, mmf.MEM_READWRITE, 0, int(size))
+	if err != nil {
+		return nil, err
+	}
+	data := region.Data()
+	base := unsafe.Pointer(&data[0])
+	return &SeqlockTable[T]{
+		shmMapping: shmMapping{obj, region},
+		header:     (*seqlockHeader)(base),
+		slots:      unsafe.Add(base, unsafe.Sizeof(seqlockHeader{})),
+		stride:     uintptr(seqlockStride(elemSize)),
+		words:      int(elemSize / 8),
+		cap:        int(capacity),
+	}, nil
+}
+
+// CreateSeqlockTable formats obj as a table of capacity zeroed slots.
+func CreateSeqlockTable[T any](obj shm.SharedMemoryObject, capacity int64) (*SeqlockTable[T], error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("invalid seqlock table capacity %d", capacity)
+	}
+	table, err := mapSeqlockTable[T](obj, capacity)
+	if err != nil {
+		return nil, err
+	}
+	h := table.header
+	atomic.StoreUint64(&h.magic, 0)
+	h.elemSize = uint64(table.words * 8)
+	h.capacity = uint64(capacity)
+	for i := 0; i < table.cap; i++ {
+		atomic.StoreUint64(table.seq(i), 0)
+		record := table.record(i)
+		for j := range record {
+			atomic.StoreUint64(&record[j], 0)
+		}
+	}
+	atomic.StoreUint64(&h.magic, seqlockMagic)
+	return table, nil
+}
+
+// AttachSeqlockTable maps a table created by CreateSeqlockTable, possibly in
+// another process, after checking that it was built for the same type size.
+func AttachSeqlockTable[T any](obj shm.SharedMemoryObject) (*SeqlockTable[T], error) {
+	h, err := readShmHeader[seqlockHeader, T](obj, seqlockMagic, "seqlock table")
+	if err != nil {
+		return nil, err
+	}
+	return mapSeqlockTable[T](obj, int64(h.capacity))
+}
+
+func (s *SeqlockTable[T]) Cap() int {
+	return s.cap
+}
+
+func (s *SeqlockTable[T]) seq(i int) *uint64 {
+	return (*uint64)(unsafe.Add(s.slots, uintptr(i)*s.stride))
+}
+
+func (s *SeqlockTable[T]) record(i int) []uint64 {
+	return unsafe.Slice((*uint64)(unsafe.Add(s.slots, uintptr(i)*s.stride+8)), s.words)
+}
+
+// Store copies *v into slot i. Only one process may store into a table.
+func (s *SeqlockTable[T]) Store(i int, v *T) {
+	if i < 0 || i >= s.cap {
+		panic(fmt.Sprintf("seqlock table index %d out of range", i))
+	}
+	seq := s.seq(i)
+	next := atomic.LoadUint64(seq) + 1
+	atomic.StoreUint64(seq, next)
+	src := unsafe.Slice((*uint64)(unsafe.Pointer(v)), s.words)
+	record := s.record(i)
+	for j := range record {
+		atomic.StoreUint64(&record[j], src[j])
+	}
+	atomic.StoreUint64(seq, next+1)
+}
+
+// TryLoad copies slot i into *v and reports whether the copy is consistent.
+// It fails if the writer was storing into the slot during the copy.
+func (s *SeqlockTable[T]) TryLoad(i int, v *T) bool {
+	_, ok := s.tryLoad(i, v)
+	return ok
+}
+
+func (s *SeqlockTable[T]) tryLoad(i int, v *T) (uint64, bool) {
+	if i < 0 || i >= s.cap {
+		panic(fmt.Sprintf("seqlock table index %d out of range", i))
+	}
+	seq := s.seq(i)
+	before := atomic.LoadUint64(seq)
+	if before&1 != 0 {
+		return before, false
+	}
+	dst := unsafe.Slice((*uint64)(unsafe.Pointer(v)), s.words)
+	record := s.record(i)
+	for j := range record {
+		dst[j] = atomic.LoadUint64(&record[j])
+	}
+	return before, atomic.LoadUint64(seq) == before
+}
+
+// Load copies slot i into *v, retrying until it gets a consistent copy, and
+// returns the number of stores the slot has seen.
+func (s *SeqlockTable[T]) Load(i int, v *T) uint64 {
+	for spins := 0; ; spins++ {
+		if seq, ok := s.tryLoad(i, v); ok {
+			return seq / 2
+		}
+		if spins&63 == 63 {
+			runtime.Gosched()
+		}
+	}
+}
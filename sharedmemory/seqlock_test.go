@@ -0,0 +1,164 @@
+package sharedmemory
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+const (
+	SEQLOCKNAME  = "SeqlockTest"
+	seqlockSlots = 8
+)
+
+func depthChecksum(d *FullDepth) int64 {
+	sum := d.Price
+	for i := range d.Asks {
+		sum = sum*31 + d.Asks[i].Price ^ d.Asks[i].Volume
+		sum = sum*31 + d.Bids[i].Price ^ d.Bids[i].Volume
+	}
+	return sum
+}
+
+func setChecksumDepth(d *FullDepth, n int64) {
+	SetRandomDepth(d, 10000+n%500, 0)
+	d.Timestamp = depthChecksum(d)
+}
+
+func verifyDepth(d *FullDepth) error {
+	if d.Timestamp != depthChecksum(d) {
+		return fmt.Errorf("torn depth at price %d", d.Price)
+	}
+	return nil
+}
+
+func TestSeqlockTable(t *testing.T) {
+	obj := createMemoryObject(SEQLOCKNAME, SeqlockTableSize[FullDepth](seqlockSlots))
+	defer obj.Destroy()
+	writer, err := CreateSeqlockTable[FullDepth](obj, seqlockSlots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+	reader, err := AttachSeqlockTable[FullDepth](initMemoryObject(SEQLOCKNAME))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	var in, out FullDepth
+	setChecksumDepth(&in, 1)
+	writer.Store(3, &in)
+	writer.Store(3, &in)
+	if n := reader.Load(3, &out); n != 2 || out != in {
+		t.Fatalf("load saw %d stores", n)
+	}
+	if _, err := AttachSeqlockTable[Level](initMemoryObject(SEQLOCKNAME)); err == nil {
+		t.Fatal("attach with wrong element size should fail")
+	}
+}
+
+func TestSeqlockTableElemSize(t *testing.T) {
+	obj := createMemoryObject(SEQLOCKNAME, SeqlockTableSize[Level](1))
+	defer obj.Destroy()
+	table, err := CreateSeqlockTable[struct{ A, B int32 }](obj, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table.Close()
+	other := initMemoryObject(SEQLOCKNAME)
+	defer other.Close()
+	if _, err := CreateSeqlockTable[struct{ A int32 }](other, 1); err == nil {
+		t.Fatal("types not a multiple of 8 bytes should be rejected")
+	}
+}
+
+func TestSeqlockTableIndex(t *testing.T) {
+	obj := createMemoryObject(SEQLOCKNAME, SeqlockTableSize[Level](2))
+	defer obj.Destroy()
+	table, err := CreateSeqlockTable[Level](obj, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer table.Close()
+	var l Level
+	for _, op := range []struct {
+		name string
+		fn   func()
+	}{
+		{"Store(5)", func() { table.Store(5, &l) }},
+		{"Store(-1)", func() { table.Store(-1, &l) }},
+		{"Load(2)", func() { table.Load(2, &l) }},
+		{"TryLoad(-1)", func() { table.TryLoad(-1, &l) }},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s should panic", op.name)
+				}
+			}()
+			op.fn()
+		}()
+	}
+}
+
+// TestSeqlockReaderProcess is the body of the child processes started by
+// TestSeqlockMultiProcess; it does nothing when run directly.
+func TestSeqlockReaderProcess(t *testing.T) {
+	reads := int(readerCount(t))
+	table, err := AttachSeqlockTable[FullDepth](initMemoryObject(SEQLOCKNAME))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer table.Close()
+	var depth FullDepth
+	var last [seqlockSlots]uint64
+	for n := 0; n < reads; n++ {
+		i := n % seqlockSlots
+		seq := table.Load(i, &depth)
+		if err := verifyDepth(&depth); err != nil {
+			t.Fatalf("slot %d: %s", i, err)
+		}
+		if seq < last[i] {
+			t.Fatalf("slot %d went back from %d to %d stores", i, last[i], seq)
+		}
+		last[i] = seq
+	}
+}
+
+func TestSeqlockMultiProcess(t *testing.T) {
+	if testing.Short() {
+		t.Skip("forks child processes")
+	}
+	const children, reads = 4, 20000
+	obj := createMemoryObject(SEQLOCKNAME, SeqlockTableSize[FullDepth](seqlockSlots))
+	defer obj.Destroy()
+	table, err := CreateSeqlockTable[FullDepth](obj, seqlockSlots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer table.Close()
+	var depth FullDepth
+	for i := 0; i < seqlockSlots; i++ {
+		setChecksumDepth(&depth, int64(i))
+		table.Store(i, &depth)
+	}
+
+	done := forkReaders(children, "TestSeqlockReaderProcess", reads)
+
+	deadline := time.After(time.Minute)
+	for n, finished := int64(0), 0; finished < children; n++ {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Error(err)
+			}
+			finished++
+		case <-deadline:
+			t.Fatal("readers did not finish")
+		default:
+			setChecksumDepth(&depth, n)
+			table.Store(int(n%seqlockSlots), &depth)
+		}
+	}
+}
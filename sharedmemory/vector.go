@@ -0,0 +1,157 @@
+package sharedmemory
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"bitbucket.org/avd/go-ipc/mmf"
+	"bitbucket.org/avd/go-ipc/shm"
+)
+
+const shmVectorMagic uint64 = 0x4345564d48534600 // "\x00FSHMVEC"
+
+// shmVectorHeader sits at offset 0 of the table. length is published by the
+// single writer after the element it covers has been copied in.
+type shmVectorHeader struct {
+	shmHeader
+	_      [cacheLine - 24]byte
+	length int64
+	_      [cacheLine - 8]byte
+}
+
+var shmVectorHeaderSize = int64(unsafe.Sizeof(shmVectorHeader{}))
+
+// ShmVectorSize returns the number of bytes a table needs to hold capacity
+// elements of T.
+func ShmVectorSize[T any](capacity int64) int64 {
+	var t T
+	return shmVectorHeaderSize + capacity*int64(unsafe.Sizeof(t))
+}
+
+// ShmVector is an append-only vector stored in the shared memory object of an
+// UnsafeSharedMemoryTable. One process appends; any number of processes read
+// the elements below Len.
+type ShmVector[T any] struct {
+	shmMapping
+	header *shmVectorHeader
+	data   []T
+}
+
+func mapShmVector[T any](obj shm.SharedMemoryObject, flag int) (*ShmVector[T], error) {
+	var t T
+	if err := checkPointerFree(reflect.TypeOf(t)); err != nil {
+		return nil, err
+	}
+	capacity := (obj.Size() - shmVectorHeaderSize) / int64(unsafe.Sizeof(t))
+	if capacity <= 0 {
+		return nil, fmt.Errorf("shm object too small for vector of %s", reflect.TypeOf(t))
+	}
+	size := ShmVectorSize[T](capacity)
+	region, err := mmf.NewMemoryRegion(obj, flag, 0, int(size))
+	if err != nil {
+		return nil, err
+	}
+	data := region.Data()
+	base := unsafe.Pointer(&data[0])
+	return &ShmVector[T]{
+		shmMapping: shmMapping{obj, region},
+		header:     (*shmVectorHeader)(base),
+		data:       unsafe.Slice((*T)(unsafe.Add(base, shmVectorHeaderSize)), capacity),
+	}, nil
+}
+
+// CreateShmVector formats the table's object as an empty vector that fills
+// the whole object.
+func CreateShmVector[T any](table *UnsafeSharedMemoryTable[T]) (*ShmVector[T], error) {
+	v, err := mapShmVector[T](table.smo, mmf.MEM_READWRITE)
+	if err != nil {
+		return nil, err
+	}
+	h := v.header
+	atomic.StoreUint64(&h.magic, 0)
+	h.elemSize = uint64(unsafe.Sizeof(v.data[0]))
+	h.capacity = uint64(len(v.data))
+	atomic.StoreInt64(&h.length, 0)
+	atomic.StoreUint64(&h.magic, shmVectorMagic)
+	return v, nil
+}
+
+// AttachShmVector maps, read-only, a vector created by CreateShmVector in
+// another process.
+func AttachShmVector[T any](table *UnsafeSharedMemoryTable[T]) (*ShmVector[T], error) {
+	h, err := readShmHeader[shmVectorHeader, T](table.smo, shmVectorMagic, "vector")
+	if err != nil {
+		return nil, err
+	}
+	v, err := mapShmVector[T](table.smo, mmf.MEM_READ_ONLY)
+	if err != nil {
+		return nil, err
+	}
+	if h.capacity > uint64(len(v.data)) {
+		v.region.Close()
+		return nil, fmt.Errorf("shm vector capacity %d exceeds object", h.capacity)
+	}
+	v.data = v.data[:h.capacity]
+	return v, nil
+}
+
+func (v *ShmVector[T]) Cap() int {
+	return len(v.data)
+}
+
+// Len returns the number of elements that are fully written.
+func (v *ShmVector[T]) Len() int {
+	return int(atomic.LoadInt64(&v.header.length))
+}
+
+// Append copies elem to the end of the vector. It must only be called by the
+// writer process.
+func (v *ShmVector[T]) Append(elem T) error {
+	l := atomic.LoadInt64(&v.header.length)
+	if l >= int64(len(v.data)) {
+		return fmt.Errorf("shm vector full at %d elements", l)
+	}
+	v.data[l] = elem
+	atomic.StoreInt64(&v.header.length, l+1)
+	return nil
+}
+
+// At returns a pointer to element i, which must be below Len. Elements never
+// change once published, so the pointer stays valid until Close.
+func (v *ShmVector[T]) At(i int) *T {
+	if i < 0 || i >= v.Len() {
+		panic(fmt.Sprintf("shm vector index %d out of range", i))
+	}
+	return &v.data[i]
+}
+
+// WaitLen blocks until at least n elements are published or ctx is done,
+// and returns the length it saw.
+func (v *ShmVector[T]) WaitLen(ctx context.Context, n int) (int, error) {
+	if n > len(v.data) {
+		return v.Len(), fmt.Errorf("shm vector can hold %d elements, waiting for %d", len(v.data), n)
+	}
+	backoff := time.Microsecond
+	for spins := 0; ; spins++ {
+		if l := v.Len(); l >= n {
+			return l, nil
+		}
+		if spins < 64 {
+			runtime.Gosched()
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return v.Len(), ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff < time.Millisecond {
+			backoff *= 2
+		}
+	}
+}
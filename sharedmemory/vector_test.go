@@ -0,0 +1,81 @@
+package sharedmemory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+const VECTORNAME = "VectorTest"
+
+func TestShmVector(t *testing.T) {
+	const capacity = 256
+	obj := createMemoryObject(VECTORNAME, ShmVectorSize[Level](capacity))
+	defer obj.Destroy()
+	writer, err := CreateShmVector(NewSharedMemoryTable[Level](obj))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+	reader, err := AttachShmVector(NewSharedMemoryTable[Level](initMemoryObject(VECTORNAME)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	if reader.Cap() != capacity || reader.Len() != 0 {
+		t.Fatalf("cap %d len %d", reader.Cap(), reader.Len())
+	}
+
+	done := make(chan error)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		for n := 1; n <= capacity; n++ {
+			if _, err := reader.WaitLen(ctx, n); err != nil {
+				done <- err
+				return
+			}
+			if l := reader.At(n - 1); l.Price != int64(n) || l.Volume != int64(-n) {
+				done <- errors.New("reader saw a partial element")
+				return
+			}
+		}
+		done <- nil
+	}()
+	for n := 1; n <= capacity; n++ {
+		if err := writer.Append(Level{Price: int64(n), Volume: int64(-n)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Append(Level{}); err == nil {
+		t.Fatal("append past capacity should fail")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := reader.WaitLen(ctx, capacity+1); err == nil {
+		t.Fatal("waiting past capacity should fail")
+	}
+	if _, err := AttachShmVector(NewSharedMemoryTable[FullDepth](initMemoryObject(VECTORNAME))); err == nil {
+		t.Fatal("attach with wrong element type should fail")
+	}
+}
+
+func TestShmVectorWaitTimeout(t *testing.T) {
+	obj := createMemoryObject(VECTORNAME, ShmVectorSize[Level](8))
+	defer obj.Destroy()
+	v, err := CreateShmVector(NewSharedMemoryTable[Level](obj))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := v.WaitLen(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline, got %v", err)
+	}
+}
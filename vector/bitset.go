@@ -0,0 +1,169 @@
+package vector
+
+import (
+	"math/bits"
+	"sync/atomic"
+)
+
+// Bitset is a fixed-size set of bits packed into 64-bit words.
+type Bitset struct {
+	words []uint64
+	n     int
+}
+
+func NewBitset(n int) *Bitset {
+	return &Bitset{words: make([]uint64, (n+63)/64), n: n}
+}
+
+func (b *Bitset) Len() int {
+	return b.n
+}
+
+func (b *Bitset) Test(i int) bool {
+	return b.words[i>>6]&(1<<(i&63)) != 0
+}
+
+func (b *Bitset) Set(i int) {
+	b.words[i>>6] |= 1 << (i & 63)
+}
+
+func (b *Bitset) Clear(i int) {
+	b.words[i>>6] &^= 1 << (i & 63)
+}
+
+// TestAndSet sets bit i and returns its previous value.
+func (b *Bitset) TestAndSet(i int) bool {
+	was := b.Test(i)
+	b.Set(i)
+	return was
+}
+
+// TestAndClear clears bit i and returns its previous value.
+func (b *Bitset) TestAndClear(i int) bool {
+	was := b.Test(i)
+	b.Clear(i)
+	return was
+}
+
+// NextSet returns the first set bit at or after from, or -1.
+func (b *Bitset) NextSet(from int) int {
+	return nextBit(len(b.words), b.n, from, false, func(i int) uint64 { return b.words[i] })
+}
+
+// NextClear returns the first clear bit at or after from, or -1.
+func (b *Bitset) NextClear(from int) int {
+	return nextBit(len(b.words), b.n, from, true, func(i int) uint64 { return b.words[i] })
+}
+
+func (b *Bitset) PopCount() int {
+	count := 0
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+func (b *Bitset) Reset() {
+	for i := range b.words {
+		b.words[i] = 0
+	}
+}
+
+// nextBit scans words from bit from, one word at a time. With invert it
+// looks for clear bits instead of set ones.
+func nextBit(nwords, n, from int, invert bool, load func(int) uint64) int {
+	if from < 0 {
+		from = 0
+	}
+	if from >= n {
+		return -1
+	}
+	i := from >> 6
+	mask := ^uint64(0) << (from & 63)
+	for ; i < nwords; i++ {
+		w := load(i)
+		if invert {
+			w = ^w
+		}
+		if w &= mask; w != 0 {
+			bit := i<<6 + bits.TrailingZeros64(w)
+			if bit >= n {
+				return -1
+			}
+			return bit
+		}
+		mask = ^uint64(0)
+	}
+	return -1
+}
+
+// AtomicBitset is a Bitset whose operations are safe for concurrent use.
+// Bits sharing a word contend on the same cache line.
+type AtomicBitset struct {
+	words []uint64
+	n     int
+}
+
+func NewAtomicBitset(n int) *AtomicBitset {
+	return &AtomicBitset{words: make([]uint64, (n+63)/64), n: n}
+}
+
+func (b *AtomicBitset) Len() int {
+	return b.n
+}
+
+func (b *AtomicBitset) Test(i int) bool {
+	return atomic.LoadUint64(&b.words[i>>6])&(1<<(i&63)) != 0
+}
+
+// TestAndSet sets bit i and returns its previous value.
+func (b *AtomicBitset) TestAndSet(i int) bool {
+	word, bit := &b.words[i>>6], uint64(1)<<(i&63)
+	for {
+		old := atomic.LoadUint64(word)
+		if old&bit != 0 {
+			return true
+		}
+		if atomic.CompareAndSwapUint64(word, old, old|bit) {
+			return false
+		}
+	}
+}
+
+// TestAndClear clears bit i and returns its previous value.
+func (b *AtomicBitset) TestAndClear(i int) bool {
+	word, bit := &b.words[i>>6], uint64(1)<<(i&63)
+	for {
+		old := atomic.LoadUint64(word)
+		if old&bit == 0 {
+			return false
+		}
+		if atomic.CompareAndSwapUint64(word, old, old&^bit) {
+			return true
+		}
+	}
+}
+
+func (b *AtomicBitset) Set(i int) {
+	b.TestAndSet(i)
+}
+
+func (b *AtomicBitset) Clear(i int) {
+	b.TestAndClear(i)
+}
+
+func (b *AtomicBitset) NextSet(from int) int {
+	return nextBit(len(b.words), b.n, from, false, func(i int) uint64 { return atomic.LoadUint64(&b.words[i]) })
+}
+
+func (b *AtomicBitset) NextClear(from int) int {
+	return nextBit(len(b.words), b.n, from, true, func(i int) uint64 { return atomic.LoadUint64(&b.words[i]) })
+}
+
+func (b *AtomicBitset) PopCount() int {
+	count := 0
+	for i := range b.words {
+		count += bits.OnesCount64(atomic.LoadUint64(&b.words[i]))
+	}
+	return count
+}
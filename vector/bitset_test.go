@@ -0,0 +1,64 @@
+package vector
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestBitset(t *testing.T) {
+	b := NewBitset(130)
+	for _, i := range []int{0, 63, 64, 129} {
+		if b.TestAndSet(i) {
+			t.Fatalf("bit %d already set", i)
+		}
+	}
+	if !b.Test(63) || b.Test(62) || b.PopCount() != 4 {
+		t.Fatalf("PopCount %d", b.PopCount())
+	}
+	if b.NextSet(1) != 63 || b.NextSet(65) != 129 || b.NextSet(130) != -1 {
+		t.Fatalf("NextSet: %d %d", b.NextSet(1), b.NextSet(65))
+	}
+	if b.NextClear(63) != 65 || b.NextClear(0) != 1 {
+		t.Fatalf("NextClear: %d", b.NextClear(63))
+	}
+	if !b.TestAndClear(64) || b.TestAndClear(64) {
+		t.Fatal("TestAndClear")
+	}
+	full := NewBitset(3)
+	full.Set(0)
+	full.Set(1)
+	full.Set(2)
+	if full.NextClear(0) != -1 {
+		t.Fatalf("NextClear past Len: %d", full.NextClear(0))
+	}
+}
+
+func TestAtomicBitset(t *testing.T) {
+	const n, workers = 1000, 4
+	b := NewAtomicBitset(n)
+	var wg sync.WaitGroup
+	claimed := make([]int, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				if !b.TestAndSet(i) {
+					claimed[w]++
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	total := 0
+	for _, c := range claimed {
+		total += c
+	}
+	if total != n || b.PopCount() != n || b.NextClear(0) != -1 {
+		t.Fatalf("claimed %d bits, PopCount %d", total, b.PopCount())
+	}
+	b.Clear(500)
+	if b.NextClear(0) != 500 || b.NextSet(500) != 501 {
+		t.Fatalf("NextClear %d", b.NextClear(0))
+	}
+}
@@ -34,3 +34,66 @@ func TestPtr(t *testing.T) {
 	i := 1
 	t.Logf("Ptr: %p\n", &i)
 }
+
+func TestVectorOps(t *testing.T) {
+	v := New[int]()
+	v.Insert(0, 1, 5)
+	v.Insert(1, 2, 3, 4)
+	t.Logf("V: %v, Cap: %d\n", v, cap(v))
+	if IndexFunc(v, func(e int) bool { return e == 3 }) != 2 {
+		t.Fatalf("insert order: %v", v)
+	}
+
+	if e := v.Remove(0); e != 1 {
+		t.Fatalf("Remove got %d", e)
+	}
+	if e := v.SwapRemove(0); e != 2 || v[0] != 5 {
+		t.Fatalf("SwapRemove got %d, V: %v", e, v)
+	}
+	if e, ok := v.PopValue(); !ok || e != 4 {
+		t.Fatalf("PopValue got %d", e)
+	}
+	backing := v[:cap(v)]
+	for i := len(v); i < len(backing); i++ {
+		if backing[i] != 0 {
+			t.Fatalf("removed slot %d not zeroed: %v", i, backing)
+		}
+	}
+
+	v.Reserve(100)
+	if cap(v)-len(v) < 100 {
+		t.Fatalf("Reserve: len %d cap %d", len(v), cap(v))
+	}
+	v.Shrink()
+	if cap(v) != len(v) {
+		t.Fatalf("Shrink: len %d cap %d", len(v), cap(v))
+	}
+	v.Truncate(1)
+	if len(v) != 1 || v[0] != 5 {
+		t.Fatalf("Truncate: %v", v)
+	}
+
+	nums := Vector[int]{1, 2, 3, 4, 5, 6}
+	even := Filter(nums, func(e int) bool { return e%2 == 0 })
+	sum := Reduce(even, 0, func(acc, e int) int { return acc + e })
+	if sum != 12 || IndexFunc(nums, func(e int) bool { return e > 10 }) != -1 {
+		t.Fatalf("Filter/Reduce: %v %d", even, sum)
+	}
+}
+
+func TestVectorInsertAliased(t *testing.T) {
+	v := Make[int](0, 8)
+	v.Append(1)
+	v.Append(2)
+	v.Append(3)
+	v.Insert(0, v[1:3]...)
+	want := []int{2, 3, 1, 2, 3}
+	if len(v) != len(want) {
+		t.Fatalf("got %v, expected %v", v, want)
+	}
+	for i := range want {
+		if v[i] != want[i] {
+			t.Fatalf("got %v, expected %v", v, want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package vector
+
+// ChunkedVector grows by allocating fixed-size chunks and never moves an
+// element once placed, so pointers returned by Append and Ptr stay valid for
+// the life of the vector.
+type ChunkedVector[T any] struct {
+	chunks [][]T
+	shift  uint
+	mask   int
+	length int
+}
+
+// NewChunked creates a ChunkedVector whose chunks hold chunkSize elements,
+// rounded up to a power of two.
+func NewChunked[T any](chunkSize int) *ChunkedVector[T] {
+	shift := uint(0)
+	for 1<<shift < chunkSize {
+		shift++
+	}
+	return &ChunkedVector[T]{shift: shift, mask: 1<<shift - 1}
+}
+
+func (v *ChunkedVector[T]) Len() int {
+	return v.length
+}
+
+func (v *ChunkedVector[T]) Cap() int {
+	return len(v.chunks) << v.shift
+}
+
+func (v *ChunkedVector[T]) Append(elem T) *T {
+	c := v.length >> v.shift
+	if c == len(v.chunks) {
+		v.chunks = append(v.chunks, make([]T, v.mask+1))
+	}
+	ptr := &v.chunks[c][v.length&v.mask]
+	*ptr = elem
+	v.length++
+	return ptr
+}
+
+func (v *ChunkedVector[T]) Ptr(i int) *T {
+	if i < 0 || i >= v.length {
+		panic("vector: chunked index out of range")
+	}
+	return &v.chunks[i>>v.shift][i&v.mask]
+}
+
+func (v *ChunkedVector[T]) At(i int) T {
+	return *v.Ptr(i)
+}
+
+func (v *ChunkedVector[T]) Set(i int, elem T) {
+	*v.Ptr(i) = elem
+}
+
+// Pop removes the last element and zeroes its slot. Chunks are kept for
+// reuse, so a pointer to a popped slot will alias the next Append.
+func (v *ChunkedVector[T]) Pop() (elem T, ok bool) {
+	if v.length == 0 {
+		return elem, false
+	}
+	ptr := v.Ptr(v.length - 1)
+	var zero T
+	elem, *ptr = *ptr, zero
+	v.length--
+	return elem, true
+}
+
+// Range calls fn for each element in order until fn returns false.
+func (v *ChunkedVector[T]) Range(fn func(i int, elem *T) bool) {
+	i := 0
+	for _, chunk := range v.chunks {
+		for j := range chunk {
+			if i == v.length || !fn(i, &chunk[j]) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+func (v *ChunkedVector[T]) Iter() *ChunkedIter[T] {
+	return &ChunkedIter[T]{v: v, i: -1}
+}
+
+type ChunkedIter[T any] struct {
+	v *ChunkedVector[T]
+	i int
+}
+
+func (it *ChunkedIter[T]) Next() bool {
+	if it.i+1 >= it.v.length {
+		return false
+	}
+	it.i++
+	return true
+}
+
+func (it *ChunkedIter[T]) Index() int {
+	return it.i
+}
+
+func (it *ChunkedIter[T]) Value() *T {
+	return &it.v.chunks[it.i>>it.v.shift][it.i&it.v.mask]
+}
@@ -0,0 +1,41 @@
+package vector
+
+import "testing"
+
+func TestChunkedVector(t *testing.T) {
+	v := NewChunked[int](3)
+	ptrs := make([]*int, 0, 100)
+	for i := 0; i < 100; i++ {
+		ptrs = append(ptrs, v.Append(i))
+	}
+	t.Logf("Len: %d, Cap: %d, Chunks: %d", v.Len(), v.Cap(), len(v.chunks))
+	for i, p := range ptrs {
+		if p != v.Ptr(i) || *p != i {
+			t.Fatalf("element %d moved or changed: %d", i, *p)
+		}
+	}
+
+	sum := 0
+	for it := v.Iter(); it.Next(); {
+		if *it.Value() != it.Index() {
+			t.Fatalf("iter at %d got %d", it.Index(), *it.Value())
+		}
+		sum += *it.Value()
+	}
+	if sum != 4950 {
+		t.Fatalf("sum %d", sum)
+	}
+
+	count := 0
+	v.Range(func(i int, elem *int) bool {
+		count++
+		return i < 9
+	})
+	if count != 10 {
+		t.Fatalf("Range visited %d", count)
+	}
+
+	if e, ok := v.Pop(); !ok || e != 99 || v.Len() != 99 {
+		t.Fatalf("Pop got %d", e)
+	}
+}
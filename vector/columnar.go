@@ -0,0 +1,134 @@
+package vector
+
+import "golang.org/x/exp/constraints"
+
+// ColumnDef maps one int64 field of a row type R to a column.
+type ColumnDef[R any] struct {
+	Name string
+	Get  func(*R) int64
+	Set  func(*R, int64)
+}
+
+// Columnar stores rows of R as one contiguous Vector[int64] per column, so a
+// scan over a single field touches only that field's memory.
+type Columnar[R any] struct {
+	defs  []ColumnDef[R]
+	cols  []Vector[int64]
+	index map[string]int
+}
+
+func NewColumnar[R any](defs ...ColumnDef[R]) *Columnar[R] {
+	c := &Columnar[R]{
+		defs:  defs,
+		cols:  make([]Vector[int64], len(defs)),
+		index: make(map[string]int, len(defs)),
+	}
+	for i, def := range defs {
+		c.index[def.Name] = i
+		c.cols[i] = New[int64]()
+	}
+	return c
+}
+
+func (c *Columnar[R]) Len() int {
+	if len(c.cols) == 0 {
+		return 0
+	}
+	return len(c.cols[0])
+}
+
+func (c *Columnar[R]) Reserve(n int) {
+	for i := range c.cols {
+		c.cols[i].Reserve(n)
+	}
+}
+
+func (c *Columnar[R]) AppendRow(row *R) {
+	for i, def := range c.defs {
+		c.cols[i].Append(def.Get(row))
+	}
+}
+
+func (c *Columnar[R]) AppendRows(rows []R) {
+	c.Reserve(len(rows))
+	for i := range rows {
+		c.AppendRow(&rows[i])
+	}
+}
+
+// Row writes row i back into dst. Fields without a column are left as is.
+func (c *Columnar[R]) Row(i int, dst *R) {
+	for j, def := range c.defs {
+		def.Set(dst, c.cols[j][i])
+	}
+}
+
+func (c *Columnar[R]) Rows() []R {
+	rows := make([]R, c.Len())
+	for i := range rows {
+		c.Row(i, &rows[i])
+	}
+	return rows
+}
+
+// Column returns the named column, sharing storage with c.
+func (c *Columnar[R]) Column(name string) (Vector[int64], bool) {
+	i, ok := c.index[name]
+	if !ok {
+		return nil, false
+	}
+	return c.cols[i], true
+}
+
+func (c *Columnar[R]) Names() []string {
+	names := make([]string, len(c.defs))
+	for i, def := range c.defs {
+		names[i] = def.Name
+	}
+	return names
+}
+
+type Number interface {
+	constraints.Integer | constraints.Float
+}
+
+func Min[T constraints.Ordered](arr Vector[T]) (m T, ok bool) {
+	if len(arr) == 0 {
+		return m, false
+	}
+	m = arr[0]
+	for _, v := range arr[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m, true
+}
+
+func Max[T constraints.Ordered](arr Vector[T]) (m T, ok bool) {
+	if len(arr) == 0 {
+		return m, false
+	}
+	m = arr[0]
+	for _, v := range arr[1:] {
+		if v > m {
+			m = v
+		}
+	}
+	return m, true
+}
+
+func Sum[T Number](arr Vector[T]) T {
+	var s T
+	for _, v := range arr {
+		s += v
+	}
+	return s
+}
+
+func Mean[T Number](arr Vector[T]) float64 {
+	if len(arr) == 0 {
+		return 0
+	}
+	return float64(Sum(arr)) / float64(len(arr))
+}
@@ -0,0 +1,43 @@
+package vector
+
+import (
+	"reflect"
+	"testing"
+)
+
+type tick struct {
+	Price  int64
+	Volume int64
+	Note   string
+}
+
+func TestColumnar(t *testing.T) {
+	c := NewColumnar(
+		ColumnDef[tick]{"price", func(r *tick) int64 { return r.Price }, func(r *tick, v int64) { r.Price = v }},
+		ColumnDef[tick]{"volume", func(r *tick) int64 { return r.Volume }, func(r *tick, v int64) { r.Volume = v }},
+	)
+	rows := []tick{{100, 5, "a"}, {102, 1, "b"}, {98, 3, "c"}}
+	c.AppendRows(rows)
+
+	price, ok := c.Column("price")
+	if !ok || !reflect.DeepEqual(price, Vector[int64]{100, 102, 98}) {
+		t.Fatalf("price column: %v", price)
+	}
+	lo, _ := Min(price)
+	hi, _ := Max(price)
+	if lo != 98 || hi != 102 || Mean(price) != 100 {
+		t.Fatalf("min=%d max=%d mean=%f", lo, hi, Mean(price))
+	}
+	volume, _ := c.Column("volume")
+	if Sum(volume) != 9 {
+		t.Fatalf("volume sum %d", Sum(volume))
+	}
+
+	back := c.Rows()
+	if back[1].Price != 102 || back[1].Volume != 1 || back[1].Note != "" {
+		t.Fatalf("row 1: %+v", back[1])
+	}
+	if _, ok := c.Column("note"); ok {
+		t.Fatal("unknown column")
+	}
+}
@@ -0,0 +1,159 @@
+package vector
+
+// Deque is a ring-backed double-ended queue. A growable Deque doubles its
+// buffer when full and keeps element order; a fixed one (NewRing) overwrites
+// the element at the opposite end instead, which suits "last N ticks"
+// windows.
+type Deque[T any] struct {
+	buf       []T
+	head      int
+	size      int
+	overwrite bool
+}
+
+func NewDeque[T any](capacity int) *Deque[T] {
+	return &Deque[T]{buf: make([]T, capacity)}
+}
+
+func NewRing[T any](capacity int) *Deque[T] {
+	if capacity <= 0 {
+		panic("vector: ring capacity must be positive")
+	}
+	return &Deque[T]{buf: make([]T, capacity), overwrite: true}
+}
+
+func (d *Deque[T]) Len() int {
+	return d.size
+}
+
+func (d *Deque[T]) Cap() int {
+	return len(d.buf)
+}
+
+func (d *Deque[T]) Full() bool {
+	return d.size == len(d.buf)
+}
+
+func (d *Deque[T]) index(i int) int {
+	i += d.head
+	if i >= len(d.buf) {
+		i -= len(d.buf)
+	}
+	return i
+}
+
+func (d *Deque[T]) grow() {
+	capacity := 2 * len(d.buf)
+	if capacity < 4 {
+		capacity = 4
+	}
+	buf := make([]T, capacity)
+	n := copy(buf, d.buf[d.head:])
+	copy(buf[n:], d.buf[:d.head])
+	d.buf = buf
+	d.head = 0
+}
+
+func (d *Deque[T]) PushBack(elem T) {
+	if d.Full() {
+		if !d.overwrite {
+			d.grow()
+		} else {
+			d.buf[d.head] = elem
+			d.head = d.index(1)
+			return
+		}
+	}
+	d.buf[d.index(d.size)] = elem
+	d.size++
+}
+
+func (d *Deque[T]) PushFront(elem T) {
+	if d.Full() {
+		if !d.overwrite {
+			d.grow()
+		} else {
+			d.head = d.index(len(d.buf) - 1)
+			d.buf[d.head] = elem
+			return
+		}
+	}
+	d.head = d.index(len(d.buf) - 1)
+	d.buf[d.head] = elem
+	d.size++
+}
+
+func (d *Deque[T]) PopFront() (elem T, ok bool) {
+	if d.size == 0 {
+		return elem, false
+	}
+	var zero T
+	elem, d.buf[d.head] = d.buf[d.head], zero
+	d.head = d.index(1)
+	d.size--
+	return elem, true
+}
+
+func (d *Deque[T]) PopBack() (elem T, ok bool) {
+	if d.size == 0 {
+		return elem, false
+	}
+	var zero T
+	i := d.index(d.size - 1)
+	elem, d.buf[i] = d.buf[i], zero
+	d.size--
+	return elem, true
+}
+
+// At returns the i-th element counting from the front. It panics if i is out
+// of range.
+func (d *Deque[T]) At(i int) T {
+	if i < 0 || i >= d.size {
+		panic("vector: deque index out of range")
+	}
+	return d.buf[d.index(i)]
+}
+
+func (d *Deque[T]) Set(i int, elem T) {
+	if i < 0 || i >= d.size {
+		panic("vector: deque index out of range")
+	}
+	d.buf[d.index(i)] = elem
+}
+
+func (d *Deque[T]) Front() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	return d.buf[d.head], true
+}
+
+func (d *Deque[T]) Back() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	return d.buf[d.index(d.size-1)], true
+}
+
+func (d *Deque[T]) Clear() {
+	var zero T
+	for i := 0; i < d.size; i++ {
+		d.buf[d.index(i)] = zero
+	}
+	d.head = 0
+	d.size = 0
+}
+
+// Vector copies the elements front to back into a new Vector.
+func (d *Deque[T]) Vector() Vector[T] {
+	v := make(Vector[T], d.size)
+	end := d.head + d.size
+	if end > len(d.buf) {
+		end = len(d.buf)
+	}
+	n := copy(v, d.buf[d.head:end])
+	copy(v[n:], d.buf[:d.size-n])
+	return v
+}
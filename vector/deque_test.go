@@ -0,0 +1,51 @@
+package vector
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeque(t *testing.T) {
+	d := NewDeque[int](0)
+	for i := 0; i < 5; i++ {
+		d.PushBack(i)
+		d.PushFront(-i - 1)
+	}
+	want := Vector[int]{-5, -4, -3, -2, -1, 0, 1, 2, 3, 4}
+	if got := d.Vector(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if d.At(0) != -5 || d.At(9) != 4 {
+		t.Fatalf("At: %d %d", d.At(0), d.At(9))
+	}
+	if v, ok := d.PopFront(); !ok || v != -5 {
+		t.Fatalf("PopFront: %d", v)
+	}
+	if v, ok := d.PopBack(); !ok || v != 4 {
+		t.Fatalf("PopBack: %d", v)
+	}
+	t.Logf("Len: %d, Cap: %d, V: %v", d.Len(), d.Cap(), d.Vector())
+	for d.Len() > 0 {
+		d.PopBack()
+	}
+	if _, ok := d.PopFront(); ok {
+		t.Fatal("PopFront on empty deque")
+	}
+}
+
+func TestRing(t *testing.T) {
+	r := NewRing[int](3)
+	for i := 1; i <= 5; i++ {
+		r.PushBack(i)
+	}
+	if got := r.Vector(); !reflect.DeepEqual(got, Vector[int]{3, 4, 5}) {
+		t.Fatalf("last 3 ticks: %v", got)
+	}
+	r.PushFront(0)
+	if got := r.Vector(); !reflect.DeepEqual(got, Vector[int]{0, 3, 4}) {
+		t.Fatalf("after PushFront: %v", got)
+	}
+	if r.Cap() != 3 {
+		t.Fatalf("ring grew to %d", r.Cap())
+	}
+}
@@ -0,0 +1,94 @@
+package vector
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ctxCheckEvery is how many elements a worker handles between ctx checks.
+const ctxCheckEvery = 256
+
+// ParallelForEach calls fn for every element, splitting arr into chunks that
+// workers goroutines pick up in turn. It stops on the first error or when ctx
+// is done, and returns that error; once every element has been handled it
+// returns nil even if ctx is done by then. workers <= 0 means GOMAXPROCS.
+func ParallelForEach[T any](ctx context.Context, arr Vector[T], fn func(i int, elem T) error, workers int) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(arr) {
+		workers = len(arr)
+	}
+	if workers == 0 {
+		return nil
+	}
+	// a few chunks per worker evens out uneven element costs
+	chunk := len(arr) / (workers * 4)
+	if chunk == 0 {
+		chunk = 1
+	}
+
+	var next, done atomic.Int64
+	var stopped atomic.Bool
+	var once sync.Once
+	var firstErr error
+	fail := func(err error) {
+		once.Do(func() { firstErr = err })
+		stopped.Store(true)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !stopped.Load() {
+				start := int(next.Add(int64(chunk))) - chunk
+				if start >= len(arr) {
+					return
+				}
+				end := start + chunk
+				if end > len(arr) {
+					end = len(arr)
+				}
+				for i := start; i < end; i++ {
+					if (i-start)%ctxCheckEvery == 0 {
+						if stopped.Load() {
+							return
+						}
+						if err := ctx.Err(); err != nil {
+							fail(err)
+							return
+						}
+					}
+					if err := fn(i, arr[i]); err != nil {
+						fail(err)
+						return
+					}
+				}
+				done.Add(int64(end - start))
+			}
+		}()
+	}
+	wg.Wait()
+	if done.Load() == int64(len(arr)) {
+		return nil
+	}
+	return firstErr
+}
+
+// ParallelMap is MapVector spread over workers goroutines. The result keeps
+// the order of arr; on error it is nil.
+func ParallelMap[T any, R any](ctx context.Context, arr Vector[T], mapper func(T) (R, error), workers int) (Vector[R], error) {
+	result := make(Vector[R], len(arr))
+	err := ParallelForEach(ctx, arr, func(i int, elem T) (err error) {
+		result[i], err = mapper(elem)
+		return
+	}, workers)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package vector
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParallelMap(t *testing.T) {
+	arr := Make[int](1000, 1000)
+	for i := range arr {
+		arr[i] = i
+	}
+	result, err := ParallelMap(context.Background(), arr, func(e int) (int, error) {
+		return e * 2, nil
+	}, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, e := range result {
+		if e != i*2 {
+			t.Fatalf("result[%d] = %d", i, e)
+		}
+	}
+}
+
+func TestParallelForEachError(t *testing.T) {
+	arr := Make[int](10000, 10000)
+	stop := errors.New("stop")
+	var calls atomic.Int64
+	err := ParallelForEach(context.Background(), arr, func(i int, _ int) error {
+		calls.Add(1)
+		if i == 10 {
+			return stop
+		}
+		return nil
+	}, 4)
+	if err != stop {
+		t.Fatalf("got %v, want %v", err, stop)
+	}
+	t.Logf("calls before stop: %d", calls.Load())
+	if calls.Load() == int64(len(arr)) {
+		t.Fatal("work continued after error")
+	}
+}
+
+func TestParallelForEachCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	arr := Make[int](100, 100)
+	if _, err := ParallelMap(ctx, arr, func(e int) (int, error) { return e, nil }, 2); err != context.Canceled {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestParallelForEachCancelMidChunk(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	arr := Make[int](1<<16, 1<<16)
+	var calls atomic.Int64
+	err := ParallelForEach(ctx, arr, func(i int, _ int) error {
+		if calls.Add(1) == 10 {
+			cancel()
+		}
+		return nil
+	}, 1)
+	if err != context.Canceled {
+		t.Fatalf("got %v", err)
+	}
+	if n := calls.Load(); n > 10+ctxCheckEvery {
+		t.Fatalf("%d calls after cancel", n-10)
+	}
+}
+
+func TestParallelMapCancelAfterDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	arr := Make[int](100, 100)
+	var calls atomic.Int64
+	result, err := ParallelMap(ctx, arr, func(e int) (int, error) {
+		if calls.Add(1) == int64(len(arr)) {
+			cancel()
+		}
+		return e + 1, nil
+	}, 1)
+	if err != nil || len(result) != len(arr) || result[99] != 1 {
+		t.Fatalf("complete result dropped: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package vector
+
+import "golang.org/x/exp/constraints"
+
+// SortedVector keeps key/value pairs ordered by key in two parallel slices.
+// Lookups are binary searches and updates shift the tail with copy, which
+// beats a tree for small books. Method names follow rbtree.RBTree.
+type SortedVector[K constraints.Ordered, V any] struct {
+	keys   Vector[K]
+	values Vector[V]
+}
+
+func NewSorted[K constraints.Ordered, V any](capacity int) *SortedVector[K, V] {
+	return &SortedVector[K, V]{
+		keys:   Make[K](0, capacity),
+		values: Make[V](0, capacity),
+	}
+}
+
+// search returns the first position whose key is >= key.
+func (s *SortedVector[K, V]) search(key K) int {
+	lo, hi := 0, len(s.keys)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if s.keys[mid] < key {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+func (s *SortedVector[K, V]) Len() int {
+	return len(s.keys)
+}
+
+// At returns the i-th smallest pair.
+func (s *SortedVector[K, V]) At(i int) (K, V) {
+	return s.keys[i], s.values[i]
+}
+
+// Insert adds key or replaces its value if already present.
+func (s *SortedVector[K, V]) Insert(key K, value V) {
+	i := s.search(key)
+	if i < len(s.keys) && s.keys[i] == key {
+		s.values[i] = value
+		return
+	}
+	var k K
+	var v V
+	s.keys = append(s.keys, k)
+	s.values = append(s.values, v)
+	copy(s.keys[i+1:], s.keys[i:])
+	copy(s.values[i+1:], s.values[i:])
+	s.keys[i] = key
+	s.values[i] = value
+}
+
+func (s *SortedVector[K, V]) Delete(key K) bool {
+	i := s.search(key)
+	if i == len(s.keys) || s.keys[i] != key {
+		return false
+	}
+	last := len(s.keys) - 1
+	copy(s.keys[i:], s.keys[i+1:])
+	copy(s.values[i:], s.values[i+1:])
+	var k K
+	var v V
+	s.keys[last] = k
+	s.values[last] = v
+	s.keys = s.keys[:last]
+	s.values = s.values[:last]
+	return true
+}
+
+func (s *SortedVector[K, V]) Get(key K) (value V, ok bool) {
+	i := s.search(key)
+	if i < len(s.keys) && s.keys[i] == key {
+		return s.values[i], true
+	}
+	return
+}
+
+// Floor returns the pair with the largest key <= key.
+func (s *SortedVector[K, V]) Floor(key K) (k K, v V, ok bool) {
+	i := s.search(key)
+	if i < len(s.keys) && s.keys[i] == key {
+		return s.keys[i], s.values[i], true
+	}
+	if i == 0 {
+		return
+	}
+	return s.keys[i-1], s.values[i-1], true
+}
+
+// Ceiling returns the pair with the smallest key >= key.
+func (s *SortedVector[K, V]) Ceiling(key K) (k K, v V, ok bool) {
+	i := s.search(key)
+	if i == len(s.keys) {
+		return
+	}
+	return s.keys[i], s.values[i], true
+}
+
+// Range calls fn for every pair with lo <= key < hi in ascending order until
+// fn returns false.
+func (s *SortedVector[K, V]) Range(lo, hi K, fn func(key K, value V) bool) {
+	for i := s.search(lo); i < len(s.keys) && s.keys[i] < hi; i++ {
+		if !fn(s.keys[i], s.values[i]) {
+			return
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package vector
+
+import (
+	"hf-utils/rbtree"
+	"math/rand"
+	"testing"
+)
+
+func TestSortedVector(t *testing.T) {
+	s := NewSorted[int64, string](4)
+	for _, k := range []int64{50, 10, 40, 20, 30} {
+		s.Insert(k, "v")
+	}
+	s.Insert(30, "x")
+	if s.Len() != 5 {
+		t.Fatalf("Len: %d", s.Len())
+	}
+	for i := 1; i < s.Len(); i++ {
+		prev, _ := s.At(i - 1)
+		cur, _ := s.At(i)
+		if prev >= cur {
+			t.Fatalf("not sorted: %v", s.keys)
+		}
+	}
+	if v, ok := s.Get(30); !ok || v != "x" {
+		t.Fatalf("Get(30) = %q", v)
+	}
+	if k, _, ok := s.Floor(35); !ok || k != 30 {
+		t.Fatalf("Floor(35) = %d", k)
+	}
+	if _, _, ok := s.Floor(5); ok {
+		t.Fatal("Floor(5) should not exist")
+	}
+	if k, _, ok := s.Ceiling(35); !ok || k != 40 {
+		t.Fatalf("Ceiling(35) = %d", k)
+	}
+	if _, _, ok := s.Ceiling(55); ok {
+		t.Fatal("Ceiling(55) should not exist")
+	}
+	var keys []int64
+	s.Range(20, 50, func(k int64, v string) bool {
+		keys = append(keys, k)
+		return true
+	})
+	if len(keys) != 3 || keys[0] != 20 || keys[2] != 40 {
+		t.Fatalf("Range: %v", keys)
+	}
+	if !s.Delete(10) || s.Delete(10) || s.Len() != 4 {
+		t.Fatal("Delete failed")
+	}
+	t.Logf("Keys: %v, Values: %v", s.keys, s.values)
+}
+
+type book interface {
+	Insert(key int64, value int64)
+	Get(key int64) (int64, bool)
+}
+
+// benchmarkBook replays level updates and lookups on a book of levels keys.
+// Deletes are left out: RBTree.Delete currently panics in fixDelete under
+// random removal.
+func benchmarkBook(b *testing.B, bk book, levels int64) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := int64(0); i < levels; i++ {
+		bk.Insert(10000+i*2, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		price := 10000 + rnd.Int63n(levels*2)
+		if i%2 == 0 {
+			bk.Insert(price, int64(i))
+		} else {
+			bk.Get(price)
+		}
+	}
+}
+
+func BenchmarkSortedVector64(b *testing.B) {
+	benchmarkBook(b, NewSorted[int64, int64](128), 64)
+}
+
+func BenchmarkRBTree64(b *testing.B) {
+	benchmarkBook(b, &rbtree.RBTree[int64, int64]{}, 64)
+}
+
+func BenchmarkSortedVector1024(b *testing.B) {
+	benchmarkBook(b, NewSorted[int64, int64](2048), 1024)
+}
+
+func BenchmarkRBTree1024(b *testing.B) {
+	benchmarkBook(b, &rbtree.RBTree[int64, int64]{}, 1024)
+}
@@ -1,5 +1,7 @@
 package vector
 
+import "unsafe"
+
 type Vector[T any] []T
 
 func New[T any]() Vector[T] {
@@ -21,10 +23,108 @@ func (v *Vector[T]) Append(elem T) {
 }
 
 func (v *Vector[T]) Pop() {
+	v.PopValue()
+}
+
+// PopValue removes and returns the last element.
+func (v *Vector[T]) PopValue() (elem T, ok bool) {
+	l := len(*v)
+	if l == 0 {
+		return elem, false
+	}
+	var zero T
+	elem, (*v)[l-1] = (*v)[l-1], zero
+	*v = (*v)[:l-1]
+	return elem, true
+}
+
+// Insert places elems before index i, shifting the tail right.
+func (v *Vector[T]) Insert(i int, elems ...T) {
+	l := len(*v)
+	if i < 0 || i > l {
+		panic("vector: insert index out of range")
+	}
+	v.Reserve(len(elems))
+	if overlaps(*v, elems) {
+		elems = append([]T(nil), elems...)
+	}
+	*v = (*v)[:l+len(elems)]
+	copy((*v)[i+len(elems):], (*v)[i:l])
+	copy((*v)[i:], elems)
+}
+
+// overlaps reports whether elems shares memory with the backing array of v.
+func overlaps[T any](v Vector[T], elems []T) bool {
+	if len(elems) == 0 || cap(v) == 0 || unsafe.Sizeof(elems[0]) == 0 {
+		return false
+	}
+	base := uintptr(unsafe.Pointer(&v[:cap(v)][0]))
+	end := base + uintptr(cap(v))*unsafe.Sizeof(elems[0])
+	p := uintptr(unsafe.Pointer(&elems[0]))
+	return p+uintptr(len(elems))*unsafe.Sizeof(elems[0]) > base && p < end
+}
+
+// Remove deletes and returns the element at i, keeping order.
+func (v *Vector[T]) Remove(i int) T {
+	elem := (*v)[i]
+	last := len(*v) - 1
+	copy((*v)[i:], (*v)[i+1:])
+	var zero T
+	(*v)[last] = zero
+	*v = (*v)[:last]
+	return elem
+}
+
+// SwapRemove deletes and returns the element at i in O(1) by moving the last
+// element into its place.
+func (v *Vector[T]) SwapRemove(i int) T {
+	elem := (*v)[i]
+	last := len(*v) - 1
+	var zero T
+	(*v)[i], (*v)[last] = (*v)[last], zero
+	*v = (*v)[:last]
+	return elem
+}
+
+// Truncate shortens the vector to n elements; it is a no-op if n >= Len.
+func (v *Vector[T]) Truncate(n int) {
+	if n < 0 {
+		n = 0
+	}
+	if n >= len(*v) {
+		return
+	}
+	var zero T
+	tail := (*v)[n:]
+	for i := range tail {
+		tail[i] = zero
+	}
+	*v = (*v)[:n]
+}
+
+// Reserve makes room for at least n more elements without reallocating.
+func (v *Vector[T]) Reserve(n int) {
 	l := len(*v)
-	if l > 0 {
-		*v = (*v)[:l-1]
+	if cap(*v)-l >= n {
+		return
+	}
+	capacity := 2 * cap(*v)
+	if capacity < l+n {
+		capacity = l + n
 	}
+	grown := make(Vector[T], l, capacity)
+	copy(grown, *v)
+	*v = grown
+}
+
+// Shrink reallocates the backing array to fit the current length.
+func (v *Vector[T]) Shrink() {
+	if len(*v) == cap(*v) {
+		return
+	}
+	shrunk := make(Vector[T], len(*v))
+	copy(shrunk, *v)
+	*v = shrunk
 }
 
 func MapVector[T any, R any](arr Vector[T], mapper func(T) R) Vector[R] {
@@ -34,3 +134,31 @@ func MapVector[T any, R any](arr Vector[T], mapper func(T) R) Vector[R] {
 	}
 	return result
 }
+
+func Filter[T any](arr Vector[T], keep func(T) bool) Vector[T] {
+	result := make(Vector[T], 0)
+	for _, v := range arr {
+		if keep(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func Reduce[T any, R any](arr Vector[T], initial R, reducer func(R, T) R) R {
+	result := initial
+	for _, v := range arr {
+		result = reducer(result, v)
+	}
+	return result
+}
+
+// IndexFunc returns the index of the first element satisfying match, or -1.
+func IndexFunc[T any](arr Vector[T], match func(T) bool) int {
+	for i, v := range arr {
+		if match(v) {
+			return i
+		}
+	}
+	return -1
+}